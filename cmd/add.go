@@ -0,0 +1,77 @@
+package cmd
+
+/*
+Copyright © 2025 SAMMY SAMMY@KOZYKODING.COM
+*/
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kozykoding/gokozyy/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagAddDir     string
+	flagAddDB      string
+	flagAddForce   bool
+	flagAddOffline bool
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add <feature>",
+	Short: "Add a feature (docker, shadcn, db) to an existing project",
+	Long: `Run the same generator steps create uses against an existing
+gokozyy project, e.g.
+
+  gokozyy add docker
+  gokozyy add db --db postgres
+  gokozyy add shadcn
+
+The project's options are read from its .gokozyy.json manifest (or
+inferred from its layout). If a file that would be written was changed
+since gokozyy generated it, add refuses unless --force is given.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: optionValueList(generator.FeatureOptions),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		req := generator.AddRequest{
+			Dir:     flagAddDir,
+			Feature: args[0],
+			DB:      flagAddDB,
+			Force:   flagAddForce,
+		}
+
+		report, err := generator.AddFeature(cmd.Context(), req, generator.Options{Offline: flagAddOffline, Progress: cmd.OutOrStdout()})
+		if errors.Is(err, generator.ErrDiverged) {
+			return fmt.Errorf("%w\n  re-run with --force to overwrite them", err)
+		}
+		if err != nil {
+			return err
+		}
+		printReport(report)
+
+		fmt.Println()
+		fmt.Printf("✅ Added %s to %s\n", req.Feature, flagAddDir)
+		return nil
+	},
+}
+
+func optionValueList(opts []generator.Option) []string {
+	values := make([]string, len(opts))
+	for i, o := range opts {
+		values[i] = o.Value
+	}
+	return values
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+
+	f := addCmd.Flags()
+	f.StringVarP(&flagAddDir, "dir", "C", ".", "project directory")
+	f.StringVar(&flagAddDB, "db", "", "database driver for the db feature: postgres, sqlite")
+	f.BoolVar(&flagAddForce, "force", false, "overwrite files that changed since generation")
+	f.BoolVar(&flagAddOffline, "offline", false, "resolve Go modules from the local cache or a file:// GOPROXY only")
+}
@@ -5,59 +5,108 @@ Copyright © 2025 SAMMY SAMMY@KOZYKODING.COM
 */
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/kozykoding/gokozyy/internal/generator"
 	"github.com/kozykoding/gokozyy/internal/ui"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
 // flags for non-interactive mode
 var (
 	flagName      string
+	flagModule    string
 	flagFramework string
+	flagLayout    string
 	flagDB        string
+	flagFrontend  string
+	flagRuntime   string
+	flagDocker    bool
+	flagEmbed     bool
 	flagNoTUI     bool
+	flagDryRun    bool
+	flagFormat    string
+	flagKeep      bool
+	flagForce     bool
+	flagMerge     bool
+	flagOffline   bool
+	flagPort      int
+	flagTimeout   time.Duration
 )
 
 // createCmd represents the create command
 var createCmd = &cobra.Command{
 	Use:   "create",
-	Short: "Create a new Go + Vite project with Bun",
+	Short: "Create a new Go + Vite project",
 	Long: `A quick way to start up / bootstrap a project with
 separate front and backends. 
 
 Run the gokozyy create command inside the directory where you want 
 your new project folder to be created.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m := ui.NewWizardModel()
-		p := tea.NewProgram(m)
+		var cfg generator.Config
+		if flagNoTUI || !stdinIsTerminal() {
+			cfg = configFromFlags(cmd.ErrOrStderr())
+		} else {
+			res, err := runWizard()
+			if err != nil {
+				return err
+			}
+			if !res.Confirmed {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+			cfg = generator.Config{
+				ProjectName: res.ProjectName,
+				ModulePath:  res.ModulePath,
+				Framework:   res.Framework,
+				Layout:      res.Layout,
+				DBDriver:    res.DBDriver,
+				Frontend:    res.Frontend,
+				Runtime:     res.Runtime,
+				UseDocker:   res.UseDocker,
+				Port:        res.Port,
 
-		finalModel, err := p.Run()
-		if err != nil {
+				EmbedFrontend: res.EmbedFrontend,
+			}
+		}
+		cfg = cfg.WithDefaults()
+
+		if err := cfg.Validate(); err != nil {
 			return err
 		}
 
-		wm := finalModel.(ui.WizardModel)
-		res := wm.Result()
-		if !res.Confirmed {
-			fmt.Println("Cancelled.")
-			return nil
+		if flagDryRun {
+			return printPlan(cfg)
 		}
 
-		cfg := generator.Config{
-			ProjectName: res.ProjectName,
-			Framework:   res.Framework,
-			DBDriver:    res.DBDriver,
-			Frontend:    res.Frontend,
-			Runtime:     res.Runtime,
-			UseDocker:   res.UseDocker,
+		opts := generator.Options{
+			KeepOnFailure:  flagKeep,
+			Offline:        flagOffline,
+			Progress:       cmd.OutOrStdout(),
+			CommandTimeout: flagTimeout,
+		}
+		switch {
+		case flagForce:
+			opts.Existing = generator.ExistForce
+		case flagMerge:
+			opts.Existing = generator.ExistMerge
 		}
 
-		if err := generator.Generate(cfg); err != nil {
+		report, err := generator.Generate(cmd.Context(), cfg, opts)
+		if errors.Is(err, generator.ErrTargetExists) {
+			return fmt.Errorf("%w\n  re-run with --force to overwrite or --merge to only add missing files", err)
+		}
+		if err != nil {
 			return err
 		}
+		printReport(report)
 
 		fmt.Println()
 		fmt.Printf("✅ Project %q created successfully!\n", cfg.ProjectName)
@@ -71,7 +120,16 @@ your new project folder to be created.`,
 		}
 
 		fmt.Println("  3. make watch              # Start backend with hot-reload (Air)")
-		fmt.Println("  4. cd frontend && bun dev   # Start React frontend environment")
+		if cfg.HasFrontend() {
+			pm, _ := generator.LookupPackageManager(cfg.Runtime)
+			fmt.Printf("  4. cd frontend && %-13s # Start the Vite dev server\n", pm.Run("dev"))
+		}
+		if cfg.UsesTempl() {
+			fmt.Printf("  4. open http://localhost:%d  # templ + htmx UI served by the backend\n", cfg.Port)
+		}
+		if cfg.EmbedFrontend {
+			fmt.Println("  5. make build              # Single binary with the frontend embedded")
+		}
 		fmt.Println()
 		fmt.Println("Happy coding!")
 
@@ -79,6 +137,114 @@ your new project folder to be created.`,
 	},
 }
 
+// runWizard runs the interactive TUI and returns what the user picked.
+func runWizard() (ui.Result, error) {
+	p := tea.NewProgram(ui.NewWizardModel())
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return ui.Result{}, err
+	}
+
+	wm := finalModel.(ui.WizardModel)
+	return wm.Result(), nil
+}
+
+// printReport lists steps skipped and conflicts left by --force/--merge.
+func printReport(r *generator.Report) {
+	if len(r.Skipped) > 0 {
+		fmt.Println()
+		fmt.Println("⏭  Skipped (already present):")
+		for _, s := range r.Skipped {
+			fmt.Printf("  - %s\n", s)
+		}
+	}
+	if len(r.Conflicts) > 0 {
+		fmt.Println()
+		fmt.Println("⚠️  Conflicts (existing file differs, left untouched):")
+		for _, c := range r.Conflicts {
+			fmt.Printf("  - %s\n", c)
+		}
+	}
+}
+
+// printPlan shows what create would do for cfg without touching disk.
+func printPlan(cfg generator.Config) error {
+	plan, err := generator.BuildPlan(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch flagFormat {
+	case "json":
+		return plan.WriteJSON(os.Stdout)
+	case "tree":
+		return plan.WriteTree(os.Stdout)
+	default:
+		return fmt.Errorf("invalid format %q (valid: tree, json)", flagFormat)
+	}
+}
+
+// configFromFlags builds a Config for non-interactive runs. Notices go to
+// w, not stdout, which --dry-run --format json keeps machine-readable.
+func configFromFlags(w io.Writer) generator.Config {
+	name := generator.NormalizeProjectName(flagName)
+	if name != flagName {
+		fmt.Fprintf(w, "◦ Using project name %q (normalized from %q)\n", name, flagName)
+	}
+	module := flagModule
+	if module == "" {
+		module = generator.DefaultModulePath(name)
+	}
+	return generator.Config{
+		ProjectName: name,
+		ModulePath:  module,
+		Framework:   flagFramework,
+		Layout:      flagLayout,
+		DBDriver:    flagDB,
+		Frontend:    flagFrontend,
+		Runtime:     flagRuntime,
+		UseDocker:   flagDocker,
+		Port:        flagPort,
+
+		EmbedFrontend: flagEmbed,
+	}
+}
+
+func stdinIsTerminal() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
 func init() {
 	rootCmd.AddCommand(createCmd)
+
+	f := createCmd.Flags()
+	f.StringVarP(&flagName, "name", "n", "my-project", "project name (non-interactive)")
+	f.StringVar(&flagModule, "module", "",
+		"Go module path for backend/ (default <prefix>/<name>/backend, prefix from\n"+
+			"~/.config/gokozyy/config.json module_prefix or git config github.user)")
+	f.StringVarP(&flagFramework, "framework", "f", "std",
+		"backend framework: "+generator.OptionValues(generator.FrameworkOptions()))
+	f.StringVar(&flagLayout, "layout", "flat",
+		"backend layout: "+generator.OptionValues(generator.LayoutOptions))
+	f.StringVar(&flagDB, "db", "none",
+		"database driver: "+generator.OptionValues(generator.DBOptions))
+	f.StringVar(&flagFrontend, "frontend", "vite-react-tailwind",
+		"frontend stack: "+generator.OptionValues(generator.FrontendOptions))
+	f.StringVar(&flagRuntime, "runtime", "bun",
+		"JavaScript runtime / package manager: "+generator.OptionValues(generator.RuntimeOptions))
+	f.IntVar(&flagPort, "port", generator.DefaultPort, "port the backend listens on (.env, Dockerfile, compose)")
+	f.BoolVar(&flagDocker, "docker", false, "scaffold Dockerfile and docker-compose.yml")
+	f.BoolVar(&flagEmbed, "embed-frontend", false, "embed the built frontend in the Go binary and serve it next to /api")
+	f.BoolVar(&flagNoTUI, "no-tui", false, "skip the wizard and build the project from flags")
+	f.BoolVar(&flagDryRun, "dry-run", false, "print the files, commands and patches without touching disk")
+	f.StringVar(&flagFormat, "format", "tree", "dry-run output format: tree, json")
+	f.BoolVar(&flagKeep, "keep-on-failure", false, "leave the partial project on disk if generation fails")
+	f.BoolVar(&flagForce, "force", false, "overwrite generated files in an existing project directory")
+	f.BoolVar(&flagMerge, "merge", false, "only write files missing from an existing project directory")
+	f.BoolVar(&flagOffline, "offline", false, "resolve Go modules from the local cache or a file:// GOPROXY only")
+	f.DurationVar(&flagTimeout, "command-timeout", generator.DefaultCommandTimeout,
+		"limit for each external command (go get, package installs, ...)")
+	createCmd.MarkFlagsMutuallyExclusive("force", "merge")
 }
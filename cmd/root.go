@@ -5,15 +5,19 @@ Copyright © 2025 SAMMY SAMMY@KOZYKODING.COM
 */
 
 import (
+	"context"
 	"os"
+	"os/signal"
 
+	"github.com/kozykoding/gokozyy/internal/generator"
 	"github.com/spf13/cobra"
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "gokozyy",
-	Short: "A brief description of your application",
+	Use:     "gokozyy",
+	Version: generator.Version,
+	Short:   "A brief description of your application",
 	Long: `A longer description that spans multiple lines and likely contains
 examples and usage of using your application. For example:
 
@@ -23,15 +27,28 @@ to quickly create a Cobra application.`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+
+	// Errors are printed on their own; usage is for flag mistakes.
+	SilenceUsage: true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(1)
+	os.Exit(run())
+}
+
+// run executes rootCmd and returns the process exit code. It is split from
+// Execute so deferred cleanup runs before os.Exit.
+func run() int {
+	// Cancel running generator commands on Ctrl-C.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		return 1
 	}
+	return 0
 }
 
 func init() {
@@ -0,0 +1,87 @@
+package cmd
+
+/*
+Copyright © 2025 SAMMY SAMMY@KOZYKODING.COM
+*/
+
+import (
+	"fmt"
+
+	"github.com/kozykoding/gokozyy/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagUpgradeDir      string
+	flagUpgradeConflict string
+	flagUpgradeDryRun   bool
+)
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Bring an existing project up to date with the current templates",
+	Long: `Re-render gokozyy's templates for the options recorded in the
+project's .gokozyy.json and three-way merge them with your files, using
+the snapshots in .gokozyy/base as the common ancestor.
+
+Files you never touched are replaced, non-overlapping changes are merged
+automatically, and overlapping ones get conflict markers (or, with
+--conflict rej, your version is kept and the template hunks go to
+<file>.rej).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		style := generator.ConflictStyle(flagUpgradeConflict)
+		if style != generator.ConflictMarkers && style != generator.ConflictReject {
+			return fmt.Errorf("invalid conflict style %q (valid: markers, rej)", flagUpgradeConflict)
+		}
+
+		req := generator.UpgradeRequest{
+			Dir:      flagUpgradeDir,
+			Conflict: style,
+			DryRun:   flagUpgradeDryRun,
+		}
+		res, err := generator.Upgrade(cmd.Context(), req, generator.Options{Progress: cmd.OutOrStdout()})
+		if err != nil {
+			return err
+		}
+
+		printFiles("Updated", res.Updated)
+		printFiles("Merged", res.Merged)
+		printFiles("Added", res.Added)
+		printFiles("Left deleted", res.Kept)
+		printFiles("Conflicts", res.Conflicted)
+
+		total := len(res.Updated) + len(res.Merged) + len(res.Added) + len(res.Conflicted)
+		switch {
+		case total == 0:
+			fmt.Println("✅ Already up to date.")
+		case flagUpgradeDryRun:
+			fmt.Println("\n(dry run: nothing was written)")
+		case len(res.Conflicted) > 0:
+			fmt.Println("\n⚠️  Resolve the conflicts above, then commit.")
+		default:
+			fmt.Println("\n✅ Upgrade complete.")
+		}
+		return nil
+	},
+}
+
+func printFiles(title string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	fmt.Printf("%s:\n", title)
+	for _, f := range files {
+		fmt.Printf("  - %s\n", f)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+
+	f := upgradeCmd.Flags()
+	f.StringVarP(&flagUpgradeDir, "dir", "C", ".", "project directory")
+	f.StringVar(&flagUpgradeConflict, "conflict", "markers", "how to record conflicts: markers, rej")
+	f.BoolVar(&flagUpgradeDryRun, "dry-run", false, "report what would change without writing")
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Dependency is a Go module the generated backend imports, pinned to the
+// version gokozyy is tested against.
+type Dependency struct {
+	Module  string
+	Version string
+}
+
+func (d Dependency) String() string {
+	return d.Module + "@" + d.Version
+}
+
+// dbDependencies are the driver modules for each DBDriver.
+var dbDependencies = map[string][]Dependency{
+	"postgres": {{Module: "github.com/jackc/pgx/v5", Version: "v5.7.5"}},
+	"sqlite":   {{Module: "github.com/mattn/go-sqlite3", Version: "v1.14.28"}},
+}
+
+// templTool is the templ code generator, added as a Go tool dependency so
+// `go tool templ` works without a global install. The generated code
+// imports the same module.
+var templTool = Dependency{Module: "github.com/a-h/templ/cmd/templ", Version: "v0.3.906"}
+
+// backendDependencies lists every module the backend generated for cfg
+// imports.
+func backendDependencies(cfg Config) ([]Dependency, error) {
+	fw, ok := LookupFramework(cfg.Framework)
+	if !ok {
+		return nil, fmt.Errorf("unknown framework %q", cfg.Framework)
+	}
+	deps := append([]Dependency(nil), fw.Deps...)
+	deps = append(deps, dbDependencies[cfg.DBDriver]...)
+	return deps, nil
+}
+
+// resolveDependencies plans `go get` of the pinned dependencies followed by
+// `go mod tidy`, so the backend builds straight after generation. It must
+// come after every Go file has been planned.
+func resolveDependencies(p *Plan, cfg Config, backendDir string) error {
+	deps, err := backendDependencies(cfg)
+	if err != nil {
+		return err
+	}
+	if len(deps) > 0 {
+		p.GoGet(backendDir, "Adding Go dependencies", deps)
+	}
+	if cfg.UsesTempl() {
+		p.RunGo(backendDir, "Adding templ", "get", "-tool", templTool.String())
+	}
+	tidyDependencies(p, cfg, backendDir)
+	return nil
+}
+
+// addDependencies plans `go get` of deps alone followed by `go mod tidy`,
+// for features added to an existing backend: the framework and templ the
+// project already requires are left at whatever version it uses.
+func addDependencies(p *Plan, cfg Config, backendDir string, deps []Dependency) {
+	p.GoGet(backendDir, "Adding Go dependencies", deps)
+	tidyDependencies(p, cfg, backendDir)
+}
+
+func tidyDependencies(p *Plan, cfg Config, backendDir string) {
+	if cfg.UsesTempl() {
+		// Tidy needs the generated components to see what they import.
+		p.RunGo(backendDir, "Generating templ components", "tool", "templ", "generate")
+	}
+	p.RunGo(backendDir, "Tidying go.mod", "mod", "tidy")
+}
+
+// missingDependencies returns the deps gomod does not require yet, at any
+// version.
+func missingDependencies(gomod []byte, deps []Dependency) []Dependency {
+	required := map[string]bool{}
+	inBlock := false
+	for line := range strings.Lines(string(gomod)) {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inBlock && fields[0] == ")":
+			inBlock = false
+		case inBlock:
+			required[fields[0]] = true
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			inBlock = true
+		case fields[0] == "require" && len(fields) > 1:
+			required[fields[1]] = true
+		}
+	}
+
+	var missing []Dependency
+	for _, d := range deps {
+		if !required[d.Module] {
+			missing = append(missing, d)
+		}
+	}
+	return missing
+}
+
+// offlineGoEnv is the environment for go commands in offline mode: modules
+// come only from the module cache, or from GOPROXY if it already points at
+// a local file:// proxy, and -mod=mod lets go.mod be updated from there.
+// -mod=mod is refused in workspace mode, so go.work is ignored.
+func offlineGoEnv() []string {
+	proxy := "off"
+	if cur := os.Getenv("GOPROXY"); strings.HasPrefix(cur, "file://") || cur == "off" {
+		proxy = cur
+	}
+
+	flags := os.Getenv("GOFLAGS")
+	if !strings.Contains(flags, "-mod=") {
+		flags = strings.TrimSpace(flags + " -mod=mod")
+	}
+	return []string{"GOPROXY=" + proxy, "GOFLAGS=" + flags, "GOWORK=off"}
+}
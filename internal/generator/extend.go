@@ -0,0 +1,300 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FeatureOptions are the features `gokozyy add` can bolt onto an existing
+// project.
+var FeatureOptions = []Option{
+	{
+		Value:       "docker",
+		Label:       "Docker",
+		Description: "Dockerfile and docker-compose.yml (plus Makefile docker targets)",
+	},
+	{
+		Value:       "shadcn",
+		Label:       "shadcn/ui",
+		Description: "shadcn/ui (shadcn-vue for Vue) components on top of the Tailwind frontend",
+	},
+	{
+		Value:       "db",
+		Label:       "Database",
+		Description: "internal/database package for Postgres or SQLite",
+	},
+}
+
+// ErrDiverged is returned by AddFeature when a file it would write was
+// changed since gokozyy generated it.
+var ErrDiverged = errors.New("files diverge from what gokozyy generated")
+
+// AddRequest describes one `gokozyy add` run.
+type AddRequest struct {
+	Dir     string // project root
+	Feature string // one of FeatureOptions
+	DB      string // driver for the "db" feature
+	// Force overwrites files even if they diverge.
+	Force bool
+}
+
+// LoadProject reads the Config of the project in dir from its manifest. A
+// project without a manifest has its Config inferred from its layout.
+func LoadProject(dir string) (Config, *Manifest, error) {
+	m, err := ReadManifest(dir)
+	if err == nil {
+		return m.Config, m, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return Config{}, nil, err
+	}
+
+	cfg, err := inferConfig(dir)
+	return cfg, nil, err
+}
+
+// inferConfig reconstructs a Config from the files a generated project
+// has on disk.
+func inferConfig(dir string) (Config, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg := Config{
+		ProjectName: filepath.Base(abs),
+		Framework:   "std",
+		DBDriver:    "none",
+		Runtime:     "bun",
+	}
+
+	gomod, err := os.ReadFile(filepath.Join(dir, "backend", "go.mod"))
+	if err != nil {
+		return cfg, fmt.Errorf("%s does not look like a gokozyy project: %w", dir, err)
+	}
+	sc := bufio.NewScanner(bytes.NewReader(gomod))
+	for sc.Scan() {
+		if mod, ok := strings.CutPrefix(strings.TrimSpace(sc.Text()), "module "); ok {
+			cfg.ModulePath = strings.Trim(strings.TrimSpace(mod), `"`)
+			break
+		}
+	}
+
+	// The framework is imported by main.go, or by the server package in
+	// the standard layout.
+	routerFile := filepath.Join(dir, "backend", "main.go")
+	if _, err := os.Stat(filepath.Join(dir, "backend", "cmd", "api", "main.go")); err == nil {
+		cfg.Layout = "standard"
+		routerFile = filepath.Join(dir, "backend", "internal", "server", "server.go")
+	}
+	if main, err := os.ReadFile(routerFile); err == nil {
+		for _, fw := range Frameworks() {
+			for _, dep := range fw.Deps {
+				if bytes.Contains(main, []byte(dep.Module)) {
+					cfg.Framework = fw.ID
+				}
+			}
+		}
+	}
+
+	if db, err := os.ReadFile(filepath.Join(dir, "backend", "internal", "database", "database.go")); err == nil {
+		switch {
+		case bytes.Contains(db, []byte("pgx")):
+			cfg.DBDriver = "postgres"
+		case bytes.Contains(db, []byte("sqlite")):
+			cfg.DBDriver = "sqlite"
+		}
+	}
+
+	for _, pm := range PackageManagers {
+		for _, lock := range pm.Lockfiles {
+			if _, err := os.Stat(filepath.Join(dir, "frontend", lock)); err == nil {
+				cfg.Runtime = pm.ID
+			}
+		}
+	}
+	cfg.Frontend = inferFrontend(dir)
+	for _, name := range []string{"Dockerfile", "docker-compose.yml"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			cfg.UseDocker = true
+		}
+	}
+	return cfg, nil
+}
+
+// inferFrontend returns the Config.Frontend value matching the project in
+// dir: its Vite stack, the templ UI, or "none".
+func inferFrontend(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, "frontend")); errors.Is(err, fs.ErrNotExist) {
+		if _, err := os.Stat(filepath.Join(dir, "backend", "internal", "web", "index.templ")); err == nil {
+			return "templ-htmx"
+		}
+		return "none"
+	}
+	stack := FrontendStacks[0]
+	if vite, err := os.ReadFile(filepath.Join(dir, "frontend", "vite.config.ts")); err == nil {
+		for _, s := range FrontendStacks {
+			if bytes.Contains(vite, []byte(s.PluginPackage)) {
+				stack = s
+			}
+		}
+	}
+	_, err := os.Stat(filepath.Join(dir, "frontend", "components.json"))
+	return stack.FrontendValue(err == nil && stack.Library != "")
+}
+
+// BuildFeaturePlan plans the generator steps for one feature against a
+// project generated with cfg, and returns cfg with the feature switched on.
+func BuildFeaturePlan(cfg Config, feature, db string) (*Plan, Config, error) {
+	p := &Plan{Root: "."}
+
+	switch feature {
+	case "docker":
+		if cfg.UseDocker {
+			return nil, cfg, fmt.Errorf("project already has docker")
+		}
+		cfg.UseDocker = true
+		if err := writeDockerFiles(p, cfg); err != nil {
+			return nil, cfg, fmt.Errorf("docker: %w", err)
+		}
+		// Makefile docker targets depend on UseDocker.
+		if err := writeMakefile(p, cfg); err != nil {
+			return nil, cfg, fmt.Errorf("makefile: %w", err)
+		}
+
+	case "shadcn":
+		if !cfg.HasFrontend() {
+			return nil, cfg, fmt.Errorf("project has no Vite frontend")
+		}
+		stack, withLibrary := frontendStack(cfg)
+		if stack.Library == "" {
+			return nil, cfg, fmt.Errorf("the %s frontend has no shadcn port", stack.Label)
+		}
+		if withLibrary {
+			return nil, cfg, fmt.Errorf("project already has %s", stack.Library)
+		}
+		cfg.Frontend = stack.FrontendValue(true)
+		if err := setupComponentLibrary(p, cfg, "frontend"); err != nil {
+			return nil, cfg, err
+		}
+
+	case "db":
+		if err := checkOption("db", db, DBOptions); err != nil || db == "none" {
+			return nil, cfg, fmt.Errorf("add db needs --db %s", "postgres or sqlite")
+		}
+		if cfg.DBDriver != "none" {
+			return nil, cfg, fmt.Errorf("project already uses %s", cfg.DBDriver)
+		}
+		cfg.DBDriver = db
+		backendDir := buildLayout(cfg).ModuleDir
+		// The config package grows the DB settings the driver reads.
+		if err := writeAppConfig(p, cfg, backendDir); err != nil {
+			return nil, cfg, fmt.Errorf("config package: %w", err)
+		}
+		if err := setupDatabase(p, cfg, backendDir); err != nil {
+			return nil, cfg, fmt.Errorf("database setup: %w", err)
+		}
+		// In the standard layout main, server and handlers take the DB.
+		if err := writeMain(p, cfg, backendDir); err != nil {
+			return nil, cfg, err
+		}
+		addDependencies(p, cfg, backendDir, dbDependencies[db])
+		// The driver reads its settings from .env.
+		if err := patchEnvFile(p, cfg); err != nil {
+			return nil, cfg, fmt.Errorf(".env: %w", err)
+		}
+		// Compose services and Makefile targets depend on the driver.
+		if cfg.UseDocker {
+			if err := writeDockerFiles(p, cfg); err != nil {
+				return nil, cfg, fmt.Errorf("docker: %w", err)
+			}
+		}
+		if err := writeMakefile(p, cfg); err != nil {
+			return nil, cfg, fmt.Errorf("makefile: %w", err)
+		}
+
+	default:
+		return nil, cfg, fmt.Errorf("unknown feature %q (valid: %s)", feature, OptionValues(FeatureOptions))
+	}
+
+	return p, cfg, nil
+}
+
+// dropUnchanged removes write steps whose content is what gokozyy last
+// generated for the file, per the manifest or the base snapshot: they
+// would change nothing of gokozyy's, so edits the user made there stay.
+func dropUnchanged(dir string, p *Plan, m *Manifest) {
+	p.Ops = slices.DeleteFunc(p.Ops, func(op Op) bool {
+		if op.Kind != OpWrite {
+			return false
+		}
+		if m != nil {
+			if e, ok := m.Entry(op.Path); ok && e.SHA256 == hashContent(op.Content) {
+				return true
+			}
+		}
+		base := ReadBase(dir, op.Path)
+		return base != nil && bytes.Equal(base, op.Content)
+	})
+}
+
+// divergedFiles lists files p would overwrite that exist with different
+// content and no longer match what gokozyy originally generated.
+func divergedFiles(dir string, p *Plan, m *Manifest) []string {
+	var out []string
+	for _, op := range p.Ops {
+		if op.Kind != OpWrite {
+			continue
+		}
+		cur, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(op.Path)))
+		if err != nil || bytes.Equal(cur, op.Content) {
+			continue
+		}
+		if m != nil {
+			if e, ok := m.Entry(op.Path); ok && e.SHA256 == hashContent(cur) {
+				continue // untouched since generation; safe to replace
+			}
+		}
+		out = append(out, op.Path)
+	}
+	return out
+}
+
+// AddFeature runs the generator steps for req.Feature against the existing
+// project in req.Dir and updates its manifest. Files that diverge from what
+// gokozyy generated are only overwritten with req.Force; otherwise the
+// error wraps ErrDiverged and lists them.
+func AddFeature(ctx context.Context, req AddRequest, opts Options) (*Report, error) {
+	cfg, m, err := LoadProject(req.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	p, cfg, err := BuildFeaturePlan(cfg, req.Feature, req.DB)
+	if err != nil {
+		return nil, err
+	}
+	p.Root = req.Dir
+
+	dropUnchanged(req.Dir, p, m)
+	if diverged := divergedFiles(req.Dir, p, m); len(diverged) > 0 && !req.Force {
+		return nil, fmt.Errorf("%w: %s", ErrDiverged, strings.Join(diverged, ", "))
+	}
+
+	if err := writeManifest(p, cfg, m); err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+
+	opts.Existing = ExistForce
+	if opts.FS != nil {
+		return p.Execute(ctx, opts)
+	}
+	return executeTracked(ctx, p, opts)
+}
@@ -0,0 +1,205 @@
+package generator
+
+import (
+	"context"
+	"path"
+	"slices"
+	"testing"
+)
+
+func TestInferConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        map[string]string
+		wantFrontend string
+		wantDocker   bool
+	}{
+		{
+			name:         "api only with docker",
+			files:        map[string]string{"Dockerfile": "FROM scratch\n"},
+			wantFrontend: "none",
+			wantDocker:   true,
+		},
+		{
+			name: "react with compose",
+			files: map[string]string{
+				"frontend/vite.config.ts": `import react from "@vitejs/plugin-react";`,
+				"docker-compose.yml":      "services: {}\n",
+			},
+			wantFrontend: "vite-react-tailwind",
+			wantDocker:   true,
+		},
+		{
+			name: "templ with compose",
+			files: map[string]string{
+				"backend/internal/web/index.templ": "package web\n",
+				"docker-compose.yml":               "services: {}\n",
+			},
+			wantFrontend: "templ-htmx",
+			wantDocker:   true,
+		},
+		{
+			name: "vue with shadcn-vue",
+			files: map[string]string{
+				"frontend/vite.config.ts":  `import vue from "@vitejs/plugin-vue";`,
+				"frontend/components.json": "{}",
+			},
+			wantFrontend: "vite-vue-tailwind-shadcn",
+		},
+		{
+			name: "svelte",
+			files: map[string]string{
+				"frontend/vite.config.ts": `import { svelte } from "@sveltejs/vite-plugin-svelte";`,
+			},
+			wantFrontend: "vite-svelte-tailwind",
+		},
+		{
+			name: "solid",
+			files: map[string]string{
+				"frontend/vite.config.ts": `import solid from "vite-plugin-solid";`,
+			},
+			wantFrontend: "vite-solid-tailwind",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"backend/go.mod": "module example.com/demo/backend\n"})
+			writeFiles(t, dir, tt.files)
+
+			cfg, err := inferConfig(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Frontend != tt.wantFrontend {
+				t.Errorf("Frontend = %q, want %q", cfg.Frontend, tt.wantFrontend)
+			}
+			if cfg.UseDocker != tt.wantDocker {
+				t.Errorf("UseDocker = %v, want %v", cfg.UseDocker, tt.wantDocker)
+			}
+			if cfg.ModulePath != "example.com/demo/backend" {
+				t.Errorf("ModulePath = %q", cfg.ModulePath)
+			}
+		})
+	}
+}
+
+func TestAddDockerRefusesExistingDockerfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"backend/go.mod": "module example.com/demo/backend\n",
+		"Dockerfile":     "FROM scratch\n",
+	})
+	cfg, err := inferConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := BuildFeaturePlan(cfg, "docker", ""); err == nil {
+		t.Fatal("add docker on a project with a Dockerfile succeeded")
+	}
+}
+
+func TestAddDBPatchesEnv(t *testing.T) {
+	cfg := Config{ProjectName: "demo", ModulePath: "example.com/demo/backend", Framework: "std", DBDriver: "none", Frontend: "none"}
+	p, _, err := BuildFeaturePlan(cfg, "db", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var patch PatchFunc
+	for _, op := range p.Ops {
+		if op.Kind == OpPatch && op.Path == ".env" {
+			patch = op.Patch
+			if op.Mode != 0o600 {
+				t.Errorf("a new .env gets mode %04o, want 0600", op.Mode)
+			}
+		}
+	}
+	if patch == nil {
+		t.Fatal("add db does not touch .env")
+	}
+
+	old := "PORT=3000\nGOKOZYY_DB_PW=secret"
+	got, err := patch([]byte(old))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := old + "\nAPP_ENV=local\n" +
+		"GOKOZYY_DB_HOST=localhost\nGOKOZYY_DB_PORT=5432\nGOKOZYY_DB_DATABASE=gokozyy\n" +
+		"GOKOZYY_DB_USERNAME=sammy\nGOKOZYY_DB_SCHEMA=public\nGOKOZYY_DB_PATH=demo.db\n"
+	if string(got) != want {
+		t.Errorf(".env =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAddDBOnlyFetchesDriver(t *testing.T) {
+	cfg := Config{ProjectName: "demo", ModulePath: "example.com/demo/backend", Framework: "gin", DBDriver: "none", Frontend: "none"}
+	p, _, err := BuildFeaturePlan(cfg, "db", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := buildLayout(cfg).ModuleDir
+
+	tests := []struct {
+		name  string
+		gomod string
+		want  []string
+	}{
+		{
+			name:  "new driver",
+			gomod: "module example.com/demo/backend\n\nrequire github.com/gin-gonic/gin v1.11.0\n",
+			want:  []string{"go get github.com/mattn/go-sqlite3@v1.14.28", "go mod tidy"},
+		},
+		{
+			name:  "driver already required",
+			gomod: "module example.com/demo/backend\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.11.0\n\tgithub.com/mattn/go-sqlite3 v1.14.32\n)\n",
+			want:  []string{"go mod tidy"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := NewMemFS()
+			if err := fsys.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := fsys.WriteFile(path.Join(dir, "go.mod"), []byte(tt.gomod), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			runner := &FakeRunner{}
+			if _, err := p.Execute(context.Background(), Options{FS: fsys, Runner: runner, Existing: ExistForce}); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, c := range runner.Calls() {
+				got = append(got, c.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("commands = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDivergedIgnoresUnchangedWrites(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Makefile":              "user makefile\n",
+		"Dockerfile":            "user dockerfile\n",
+		BaseDir + "/Dockerfile": "FROM alpine\n",
+		"main.go":               "package main // user's\n",
+	})
+	m := &Manifest{Files: []ManifestEntry{{Path: "Makefile", SHA256: hashContent([]byte("all:\n"))}}}
+
+	p := &Plan{}
+	p.Write("Makefile", []byte("all:\n"), 0o644)
+	p.Write("Dockerfile", []byte("FROM alpine\n"), 0o644)
+	p.Write("main.go", []byte("package main\n"), 0o644)
+
+	dropUnchanged(dir, p, m)
+	if got := divergedFiles(dir, p, m); !slices.Equal(got, []string{"main.go"}) {
+		t.Errorf("diverged = %v, want [main.go]", got)
+	}
+	if len(p.Ops) != 1 || p.Ops[0].Path != "main.go" {
+		t.Errorf("plan still writes %v", p.Ops)
+	}
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// Framework describes one backend framework option. Each lives in its own
+// framework_<id>.go file that registers it from init.
+type Framework struct {
+	ID          string
+	Label       string
+	Description string
+	// Order positions the framework in the wizard; lower comes first.
+	Order int
+	// Deps are the Go modules the generated code imports.
+	Deps []Dependency
+	// MainTemplate renders backend/main.go in the flat layout.
+	MainTemplate string
+	// ServerTemplate renders internal/server/server.go in the standard
+	// layout, where main.go and the handlers are shared.
+	ServerTemplate string
+	// Wire, when set, adds framework-specific steps (middleware, extra
+	// files) after main.go has been planned.
+	Wire func(p *Plan, cfg Config, backendDir string) error
+}
+
+var frameworks = map[string]Framework{}
+
+// RegisterFramework adds f to the registry. It panics on a duplicate ID,
+// which is a programming error.
+func RegisterFramework(f Framework) {
+	if _, dup := frameworks[f.ID]; dup {
+		panic(fmt.Sprintf("generator: framework %q registered twice", f.ID))
+	}
+	frameworks[f.ID] = f
+}
+
+// Frameworks returns every registered framework in wizard order.
+func Frameworks() []Framework {
+	out := make([]Framework, 0, len(frameworks))
+	for _, f := range frameworks {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Order != out[j].Order {
+			return out[i].Order < out[j].Order
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// LookupFramework returns the framework registered as id.
+func LookupFramework(id string) (Framework, bool) {
+	f, ok := frameworks[id]
+	return f, ok
+}
+
+// FrameworkOptions are the registered frameworks as wizard/flag options.
+func FrameworkOptions() []Option {
+	fws := Frameworks()
+	opts := make([]Option, len(fws))
+	for i, f := range fws {
+		opts[i] = Option{Value: f.ID, Label: f.Label, Description: f.Description}
+	}
+	return opts
+}
+
+// writeMain plans the main package, plus the server and handlers packages
+// in the standard layout, and any wiring for cfg.Framework.
+func writeMain(p *Plan, cfg Config, backendDir string) error {
+	fw, ok := LookupFramework(cfg.Framework)
+	if !ok {
+		return fmt.Errorf("unknown framework %q", cfg.Framework)
+	}
+
+	mainFile := path.Join(backendDir, buildLayout(cfg).MainPkg, "main.go")
+	if cfg.Layout == "standard" {
+		internal := path.Join(backendDir, "internal")
+		if err := p.WriteTemplate(mainFile, "main_api.go.tmpl", cfg, 0o644); err != nil {
+			return err
+		}
+		if err := p.WriteTemplate(path.Join(internal, "server", "server.go"), fw.ServerTemplate, cfg, 0o644); err != nil {
+			return err
+		}
+		if err := p.WriteTemplate(path.Join(internal, "handlers", "health.go"), "health.go.tmpl", cfg, 0o644); err != nil {
+			return err
+		}
+	} else if err := p.WriteTemplate(mainFile, fw.MainTemplate, cfg, 0o644); err != nil {
+		return err
+	}
+	if fw.Wire != nil {
+		if err := fw.Wire(p, cfg, backendDir); err != nil {
+			return fmt.Errorf("%s wiring: %w", fw.ID, err)
+		}
+	}
+	return nil
+}
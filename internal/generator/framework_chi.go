@@ -0,0 +1,13 @@
+package generator
+
+func init() {
+	RegisterFramework(Framework{
+		ID:             "chi",
+		Label:          "Chi",
+		Description:    "Lightweight, idiomatic router for Go HTTP services",
+		Order:          10,
+		Deps:           []Dependency{{Module: "github.com/go-chi/chi/v5", Version: "v5.2.3"}},
+		MainTemplate:   "main_chi.go.tmpl",
+		ServerTemplate: "server_chi.go.tmpl",
+	})
+}
@@ -0,0 +1,13 @@
+package generator
+
+func init() {
+	RegisterFramework(Framework{
+		ID:             "echo",
+		Label:          "Echo",
+		Description:    "High performance, minimalist Go web framework",
+		Order:          30,
+		Deps:           []Dependency{{Module: "github.com/labstack/echo/v4", Version: "v4.13.4"}},
+		MainTemplate:   "main_echo.go.tmpl",
+		ServerTemplate: "server_echo.go.tmpl",
+	})
+}
@@ -0,0 +1,13 @@
+package generator
+
+func init() {
+	RegisterFramework(Framework{
+		ID:             "fiber",
+		Label:          "Fiber",
+		Description:    "Express-inspired framework built on fasthttp",
+		Order:          40,
+		Deps:           []Dependency{{Module: "github.com/gofiber/fiber/v2", Version: "v2.52.9"}},
+		MainTemplate:   "main_fiber.go.tmpl",
+		ServerTemplate: "server_fiber.go.tmpl",
+	})
+}
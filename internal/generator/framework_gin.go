@@ -0,0 +1,13 @@
+package generator
+
+func init() {
+	RegisterFramework(Framework{
+		ID:             "gin",
+		Label:          "Gin",
+		Description:    "Martini-like API, high performance HTTP framework",
+		Order:          20,
+		Deps:           []Dependency{{Module: "github.com/gin-gonic/gin", Version: "v1.10.1"}},
+		MainTemplate:   "main_gin.go.tmpl",
+		ServerTemplate: "server_gin.go.tmpl",
+	})
+}
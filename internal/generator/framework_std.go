@@ -0,0 +1,12 @@
+package generator
+
+func init() {
+	RegisterFramework(Framework{
+		ID:             "std",
+		Label:          "Standard-library",
+		Description:    "The built-in Go standard library HTTP package",
+		Order:          0,
+		MainTemplate:   "main_std.go.tmpl",
+		ServerTemplate: "server_std.go.tmpl",
+	})
+}
@@ -0,0 +1,187 @@
+package generator
+
+import (
+	"fmt"
+)
+
+// FrontendStack is one create-vite framework template and the places the
+// generator hooks into it. Every stack is offered with Tailwind, and with
+// its component library on top when it has one.
+type FrontendStack struct {
+	ID    string
+	Label string
+	// Template is the create-vite template name.
+	Template string
+	// Entry is the script that imports CSS, relative to src.
+	Entry string
+	// CSS is the stylesheet create-vite generates, relative to src. It
+	// becomes the Tailwind entry.
+	CSS string
+	// Extensions are the source file extensions Tailwind scans.
+	Extensions string
+	// PluginImport, PluginPackage and Plugin wire the framework's Vite
+	// plugin into vite.config.ts.
+	PluginImport  string
+	PluginPackage string
+	Plugin        string
+	// Pages are the API client and landing page, relative to src.
+	Pages []FrontendFile
+
+	// Library names the component library, if the stack has one. See
+	// setupComponentLibrary.
+	Library string
+}
+
+// FrontendFile is a file rendered from a template into the frontend.
+type FrontendFile struct {
+	File, Template string
+}
+
+// FrontendStacks are the supported frontend frameworks, in wizard order.
+var FrontendStacks = []FrontendStack{
+	{
+		ID:            "react",
+		Label:         "React",
+		Template:      "react-ts",
+		Entry:         "main.tsx",
+		CSS:           "index.css",
+		Extensions:    "js,ts,jsx,tsx",
+		PluginImport:  "react",
+		PluginPackage: "@vitejs/plugin-react",
+		Plugin:        "react()",
+		Pages: []FrontendFile{
+			{"lib/api.ts", "api.ts.tmpl"},
+			{"components/HealthStatus.tsx", "HealthStatus.tsx.tmpl"},
+			{"App.tsx", "App.tsx.tmpl"},
+		},
+		Library: "shadcn/ui",
+	},
+	{
+		ID:            "vue",
+		Label:         "Vue",
+		Template:      "vue-ts",
+		Entry:         "main.ts",
+		CSS:           "style.css",
+		Extensions:    "vue,js,ts",
+		PluginImport:  "vue",
+		PluginPackage: "@vitejs/plugin-vue",
+		Plugin:        "vue()",
+		Pages: []FrontendFile{
+			{"lib/api.ts", "api.ts.tmpl"},
+			{"components/HealthStatus.vue", "HealthStatus.vue.tmpl"},
+			{"App.vue", "App.vue.tmpl"},
+		},
+		Library: "shadcn-vue",
+	},
+	{
+		ID:            "svelte",
+		Label:         "Svelte",
+		Template:      "svelte-ts",
+		Entry:         "main.ts",
+		CSS:           "app.css",
+		Extensions:    "svelte,js,ts",
+		PluginImport:  "{ svelte }",
+		PluginPackage: "@sveltejs/vite-plugin-svelte",
+		Plugin:        "svelte()",
+		Pages: []FrontendFile{
+			{"lib/api.ts", "api.ts.tmpl"},
+			{"lib/HealthStatus.svelte", "HealthStatus.svelte.tmpl"},
+			{"App.svelte", "App.svelte.tmpl"},
+		},
+		// No Library: shadcn-svelte assumes SvelteKit's $lib alias and
+		// adds components through its CLI from a remote registry, so the
+		// plain svelte-ts app here could not be set up from pinned
+		// templates the way shadcn/ui and shadcn-vue are, nor with
+		// --dry-run or --offline.
+	},
+	{
+		ID:            "solid",
+		Label:         "Solid",
+		Template:      "solid-ts",
+		Entry:         "index.tsx",
+		CSS:           "index.css",
+		Extensions:    "js,ts,jsx,tsx",
+		PluginImport:  "solid",
+		PluginPackage: "vite-plugin-solid",
+		Plugin:        "solid()",
+		Pages: []FrontendFile{
+			{"lib/api.ts", "api.ts.tmpl"},
+			{"components/HealthStatus.tsx", "HealthStatus.solid.tsx.tmpl"},
+			{"App.tsx", "App.solid.tsx.tmpl"},
+		},
+		// No Library: solid-ui is a community port installed only through
+		// its CLI, which fetches components at run time; see svelte above.
+	},
+}
+
+// FrontendValue is the --frontend value for stack s, with its component
+// library when withLibrary is set.
+func (s FrontendStack) FrontendValue(withLibrary bool) string {
+	v := "vite-" + s.ID + "-tailwind"
+	if withLibrary {
+		v += "-shadcn"
+	}
+	return v
+}
+
+// frontendStack returns the stack cfg.Frontend is built on and whether it
+// includes the stack's component library. It defaults to React, which was
+// the only stack before the others were added. Callers check
+// Config.HasFrontend first.
+func frontendStack(cfg Config) (FrontendStack, bool) {
+	for _, s := range FrontendStacks {
+		if cfg.Frontend == s.FrontendValue(false) {
+			return s, false
+		}
+		if s.Library != "" && cfg.Frontend == s.FrontendValue(true) {
+			return s, true
+		}
+	}
+	return FrontendStacks[0], false
+}
+
+func frontendOptions() []Option {
+	var opts []Option
+	for _, s := range FrontendStacks {
+		opts = append(opts, Option{
+			Value:       s.FrontendValue(false),
+			Label:       "Vite + " + s.Label + " + Tailwind",
+			Description: fmt.Sprintf("Basic Vite %s app styled with Tailwind CSS", s.Label),
+		})
+		if s.Library != "" {
+			opts = append(opts, Option{
+				Value:       s.FrontendValue(true),
+				Label:       "Vite + " + s.Label + " + Tailwind + " + s.Library,
+				Description: fmt.Sprintf("Vite %s app with Tailwind and %s components", s.Label, s.Library),
+			})
+		}
+	}
+	return append(opts, Option{
+		Value:       "templ-htmx",
+		Label:       "templ + htmx + Tailwind (server-rendered)",
+		Description: "Go-rendered HTML in the backend, styled by the standalone Tailwind CLI; no Node or Bun",
+	}, Option{
+		Value:       "none",
+		Label:       "None (API only)",
+		Description: "Just the Go backend; no frontend/, Node or Bun needed",
+	})
+}
+
+// setupComponentLibrary installs the component library of cfg's stack into
+// its Tailwind frontend.
+func setupComponentLibrary(p *Plan, cfg Config, frontendDir string) error {
+	stack, _ := frontendStack(cfg)
+	switch stack.ID {
+	case "react":
+		if err := setupShadcnManualV4(p, cfg, frontendDir); err != nil {
+			return fmt.Errorf("shadcn manual v4 setup: %w", err)
+		}
+	case "vue":
+		if err := setupShadcnVue(p, cfg, frontendDir); err != nil {
+			return fmt.Errorf("shadcn-vue setup: %w", err)
+		}
+	default:
+		return fmt.Errorf("the %s frontend has no component library", stack.Label)
+	}
+	return nil
+}
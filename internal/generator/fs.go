@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FS is a writable filesystem a Plan is executed against. Names are
+// slash-separated and relative to the project root.
+type FS interface {
+	MkdirAll(name string, perm fs.FileMode) error
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	ReadFile(name string) ([]byte, error)
+	Stat(name string) (fs.FileInfo, error)
+	RemoveAll(name string) error
+}
+
+// localFS is implemented by filesystems that live on disk, so external
+// commands can run inside them.
+type localFS interface {
+	Path(name string) string
+}
+
+// DiskFS is an FS rooted at a directory on disk.
+type DiskFS struct {
+	Root string
+}
+
+// Path returns the on-disk location of name.
+func (d DiskFS) Path(name string) string {
+	return filepath.Join(d.Root, filepath.FromSlash(name))
+}
+
+func (d DiskFS) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(d.Path(name), perm)
+}
+
+func (d DiskFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(d.Path(name), data, perm)
+}
+
+func (d DiskFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(d.Path(name))
+}
+
+func (d DiskFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(d.Path(name))
+}
+
+func (d DiskFS) RemoveAll(name string) error {
+	return os.RemoveAll(d.Path(name))
+}
+
+// MemFS is an in-memory FS, used for previews, archives and golden tests.
+// The zero value is not usable; call NewMemFS.
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string]memFile
+}
+
+type memFile struct {
+	data []byte
+	mode fs.FileMode
+}
+
+// NewMemFS returns an empty in-memory filesystem.
+func NewMemFS() *MemFS {
+	return &MemFS{files: map[string]memFile{".": {mode: fs.ModeDir | 0o755}}}
+}
+
+func (m *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = path.Clean(name)
+	for d := name; d != "."; d = path.Dir(d) {
+		if f, ok := m.files[d]; ok {
+			if !f.mode.IsDir() {
+				return &fs.PathError{Op: "mkdir", Path: d, Err: fs.ErrExist}
+			}
+			continue
+		}
+		m.files[d] = memFile{mode: fs.ModeDir | perm.Perm()}
+	}
+	return nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = path.Clean(name)
+	if dir, ok := m.files[path.Dir(name)]; !ok || !dir.mode.IsDir() {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrNotExist}
+	}
+	if f, ok := m.files[name]; ok && f.mode.IsDir() {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrExist}
+	}
+	m.files[name] = memFile{data: append([]byte(nil), data...), mode: perm.Perm()}
+	return nil
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, ok := m.files[path.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	if f.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = path.Clean(name)
+	f, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return memFileInfo{name: path.Base(name), file: f}, nil
+}
+
+func (m *MemFS) RemoveAll(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = path.Clean(name)
+	for k := range m.files {
+		if k == name || strings.HasPrefix(k, name+"/") || name == "." && k != "." {
+			delete(m.files, k)
+		}
+	}
+	return nil
+}
+
+// Files returns the path of every regular file, sorted.
+func (m *MemFS) Files() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []string
+	for k, f := range m.files {
+		if !f.mode.IsDir() {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+type memFileInfo struct {
+	name string
+	file memFile
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.file.data)) }
+func (i memFileInfo) Mode() fs.FileMode  { return i.file.mode }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.file.mode.IsDir() }
+func (i memFileInfo) Sys() any           { return nil }
@@ -1,60 +1,6 @@
 package generator
 
-import (
-	"os"
-	"path/filepath"
-)
-
-func writeAirConfig(cfg Config) error {
-	projectRoot := cfg.ProjectName
-
+func writeAirConfig(p *Plan, cfg Config) error {
 	// Exclude frontend directories so Air doesn't waste CPU
-	content := `root = "."
-testdata_dir = "testdata"
-tmp_dir = "tmp"
-
-[build]
-  args_bin = []
-  bin = "./main"
-  cmd = "make build"
-  delay = 1000
-  exclude_dir = ["assets", "tmp", "vendor", "testdata", "frontend/node_modules", "frontend/dist"]
-  exclude_file = []
-  exclude_regex = ["_test.go"]
-  exclude_unchanged = false
-  follow_symlink = false
-  full_bin = ""
-  include_dir = []
-  include_ext = ["go", "tpl", "tmpl", "html", "sql"]
-  include_file = []
-  kill_delay = "0s"
-  log = "build-errors.log"
-  poll = false
-  poll_interval = 0
-  post_cmd = []
-  pre_cmd = []
-  rerun = false
-  rerun_delay = 500
-  send_interrupt = false
-  stop_on_error = false
-
-[color]
-  app = ""
-  build = "yellow"
-  main = "magenta"
-  runner = "green"
-  watcher = "cyan"
-
-[log]
-  main_only = false
-  time = false
-
-[misc]
-  clean_on_exit = false
-
-[screen]
-  clear_on_rebuild = false
-  keep_scroll = true
-`
-	return os.WriteFile(filepath.Join(projectRoot, ".air.toml"), []byte(content), 0o644)
+	return p.WriteTemplate(".air.toml", "air.toml.tmpl", cfg, 0o644)
 }
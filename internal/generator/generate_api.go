@@ -0,0 +1,20 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+)
+
+// writeAPIClient adds the typed /api fetch helper and replaces the
+// create-vite landing page with one that calls /api/health through the
+// Vite proxy.
+func writeAPIClient(p *Plan, cfg Config, frontendDir string) error {
+	stack, _ := frontendStack(cfg)
+	for _, f := range stack.Pages {
+		file := path.Join(frontendDir, "src", f.File)
+		if err := p.WriteTemplate(file, f.Template, cfg, 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", path.Base(file), err)
+		}
+	}
+	return nil
+}
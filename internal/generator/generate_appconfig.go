@@ -0,0 +1,11 @@
+package generator
+
+import (
+	"path"
+)
+
+// writeAppConfig plans internal/config, the typed settings every generated
+// main and the database package read.
+func writeAppConfig(p *Plan, cfg Config, backendDir string) error {
+	return p.WriteTemplate(path.Join(backendDir, "internal", "config", "config.go"), "config.go.tmpl", cfg, 0o644)
+}
@@ -1,34 +0,0 @@
-package generator
-
-import (
-	"os"
-	"path/filepath"
-)
-
-func writeChiMain(dir string) error {
-	code := `package main
-
-import (
-	"log"
-	"net/http"
-
-	"github.com/go-chi/chi/v5"
-)
-
-func main() {
-	r := chi.NewRouter()
-
-	r.Get("/api/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Write([]byte(` + "`" + `{"status":"ok"}` + "`" + `))
-	})
-
-	addr := ":8080"
-	log.Println("Starting chi server on", addr)
-	if err := http.ListenAndServe(addr, r); err != nil {
-		log.Fatal(err)
-	}
-}
-`
-	return os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), 0o644)
-}
@@ -1,32 +0,0 @@
-package generator
-
-import (
-	"os"
-	"path/filepath"
-)
-
-func writeGinMain(dir string) error {
-	code := `package main
-
-import (
-	"log"
-
-	"github.com/gin-gonic/gin"
-)
-
-func main() {
-	r := gin.Default()
-
-	r.GET("/api/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
-
-	addr := ":8080"
-	log.Println("Starting gin server on", addr)
-	if err := r.Run(addr); err != nil {
-		log.Fatal(err)
-	}
-}
-`
-	return os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), 0o644)
-}
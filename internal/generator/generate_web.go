@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"path"
+)
+
+// writeWebPackage plans the package that embeds the frontend build, with a
+// placeholder so the backend compiles before the frontend is built.
+func writeWebPackage(p *Plan, cfg Config) error {
+	layout := buildLayout(cfg)
+	if err := p.WriteTemplate(path.Join(layout.ModuleDir, layout.WebPkg, "web.go"), "web.go.tmpl", cfg, 0o644); err != nil {
+		return err
+	}
+	p.Write(path.Join(layout.EmbedDir(), ".gitkeep"), nil, 0o644)
+	return nil
+}
+
+// writeTemplUI plans the templ + htmx UI in the web package: the handler,
+// the components, the Tailwind input and a placeholder stylesheet so the
+// backend compiles before `make generate` builds the real one.
+func writeTemplUI(p *Plan, cfg Config) error {
+	web := buildLayout(cfg).WebDir()
+	files := []struct{ file, tmpl string }{
+		{"web.go", "web_templ_ui.go.tmpl"},
+		{"layout.templ", "layout.templ.tmpl"},
+		{"index.templ", "index.templ.tmpl"},
+		{"input.css", "input.css.tmpl"},
+	}
+	for _, f := range files {
+		if err := p.WriteTemplate(path.Join(web, f.file), f.tmpl, cfg, 0o644); err != nil {
+			return err
+		}
+	}
+	p.Write(path.Join(web, "static", "styles.css"), []byte("/* Built from ../input.css by `make generate`. */\n"), 0o644)
+	return nil
+}
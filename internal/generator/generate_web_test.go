@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	templgen "github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// TestTemplComponentsGenerate runs templ over every .templ file the
+// templ-htmx frontend writes, so copy that templ reads as Go (a line of
+// text starting with "for" or "if") fails here instead of in
+// `go tool templ generate`.
+func TestTemplComponentsGenerate(t *testing.T) {
+	for _, layout := range []string{"flat", "standard"} {
+		cfg := Config{
+			ProjectName: "demo",
+			ModulePath:  "example.com/demo/backend",
+			Framework:   "std",
+			Layout:      layout,
+			DBDriver:    "none",
+			Frontend:    "templ-htmx",
+		}
+		p, err := BuildPlan(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var n int
+		for _, op := range p.Ops {
+			if op.Kind != OpWrite || !strings.HasSuffix(op.Path, ".templ") || strings.HasPrefix(op.Path, BaseDir+"/") {
+				continue
+			}
+			n++
+			tf, err := parser.ParseString(string(op.Content))
+			if err != nil {
+				t.Errorf("%s/%s: parse: %v", layout, op.Path, err)
+				continue
+			}
+			if _, err := templgen.Generate(tf, &bytes.Buffer{}); err != nil {
+				t.Errorf("%s/%s: generate: %v", layout, op.Path, err)
+			}
+		}
+		if n == 0 {
+			t.Fatalf("%s: plan wrote no .templ files", layout)
+		}
+	}
+}
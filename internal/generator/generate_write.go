@@ -1,46 +1,59 @@
 package generator
 
 import (
-	"os"
-	"path/filepath"
+	"bufio"
+	"bytes"
+	"strings"
 )
 
-func writeEnvFile(cfg Config) error {
-	envPath := filepath.Join(cfg.ProjectName, ".env")
-
-	content := `PORT=42069
-APP_ENV=local
-GOKOZYY_DB_HOST=localhost
-GOKOZYY_DB_PORT=5432
-GOKOZYY_DB_DATABASE=gokozyy
-GOKOZYY_DB_USERNAME=sammy
-GOKOZYY_DB_PW=thisismypassword
-GOKOZYY_DB_SCHEMA=public
-`
-
-	return os.WriteFile(envPath, []byte(content), 0o600)
+func writeEnvFile(p *Plan, cfg Config) error {
+	return p.WriteTemplate(".env", "env.tmpl", cfg, 0o600)
 }
 
-func writeGitignore(cfg Config) error {
-	path := filepath.Join(cfg.ProjectName, ".gitignore")
-
-	content := `.env
-# Go
-bin/
-*.exe
-*.test
-*.out
-
-# Node/Bun/Vite
-node_modules/
-dist/
-.vite/
+// patchEnvFile adds the keys .env would have for cfg but lacks, leaving
+// the values the user already set alone.
+func patchEnvFile(p *Plan, cfg Config) error {
+	env, err := renderTemplate("env.tmpl", cfg)
+	if err != nil {
+		return err
+	}
+	// Like writeEnvFile, keep a new .env private: it holds credentials.
+	p.PatchOptional(".env", "add missing settings", 0o600, func(data []byte) ([]byte, error) {
+		return addMissingEnv(data, env), nil
+	})
+	return nil
+}
 
-# IDE/editor
-.vscode/
-.idea/
-.DS_Store
-`
+// addMissingEnv appends every KEY=value line of env whose key is not set
+// in data.
+func addMissingEnv(data, env []byte) []byte {
+	if data == nil {
+		return env
+	}
+	have := map[string]bool{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		if key, _, ok := strings.Cut(strings.TrimSpace(sc.Text()), "="); ok {
+			have[strings.TrimSpace(strings.TrimPrefix(key, "export "))] = true
+		}
+	}
+
+	out := bytes.Clone(data)
+	sc = bufio.NewScanner(bytes.NewReader(env))
+	for sc.Scan() {
+		line := sc.Text()
+		key, _, ok := strings.Cut(line, "=")
+		if !ok || have[key] {
+			continue
+		}
+		if len(out) > 0 && out[len(out)-1] != '\n' {
+			out = append(out, '\n')
+		}
+		out = append(out, line+"\n"...)
+	}
+	return out
+}
 
-	return os.WriteFile(path, []byte(content), 0o644)
+func writeGitignore(p *Plan, cfg Config) error {
+	return p.WriteTemplate(".gitignore", "gitignore.tmpl", cfg, 0o644)
 }
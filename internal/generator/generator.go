@@ -1,165 +1,274 @@
 package generator
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"path"
+	"time"
 )
 
 // Config is what you’ll pass from the TUI.
 type Config struct {
-	ProjectName string
-	Framework   string // "std" | "chi" | "gin"
-	DBDriver    string // "none" | "postgres" | "sqlite"
-	Frontend    string // "vite-react-tailwind" | "vite-react-tailwind-shadcn"
-	Runtime     string // "bun"
-	UseDocker   bool   // whether to scaffold Docker for the DB
+	ProjectName string `json:"project_name"`
+	ModulePath  string `json:"module_path"` // Go module path of the backend, e.g. github.com/you/app/backend
+	Framework   string `json:"framework"`   // ID of a registered Framework
+	Layout      string `json:"layout"`      // "flat" (default) | "standard"
+	DBDriver    string `json:"db_driver"`   // "none" | "postgres" | "sqlite"
+	Frontend    string `json:"frontend"`    // one of FrontendOptions, e.g. "vite-vue-tailwind", "templ-htmx" or "none"
+	Runtime     string `json:"runtime"`     // package manager: "bun" | "npm" | "pnpm" | "yarn" | "deno"
+	UseDocker   bool   `json:"use_docker"`  // whether to scaffold Docker for the DB
+	// Port the backend listens on by default. .env, docker-compose.yml,
+	// the Dockerfile and the generated config package all derive from it.
+	Port int `json:"port,omitempty"`
+	// EmbedFrontend builds frontend/dist into the Go binary, which then
+	// serves the SPA next to /api.
+	EmbedFrontend bool `json:"embed_frontend,omitempty"`
 }
 
-func generateFrontend(cfg Config) error {
-	frontendDir := filepath.Join(cfg.ProjectName, "frontend")
+// HasFrontend reports whether the project has a Vite app in frontend/, as
+// opposed to being API-only or rendering its UI with templ.
+func (c Config) HasFrontend() bool {
+	return c.Frontend != "none" && !c.UsesTempl()
+}
 
-	fmt.Printf("◦ Scaffolding frontend in %s...\n", frontendDir)
-	fmt.Printf("  [gokozyy] cfg.Frontend = %q\n", cfg.Frontend)
+// UsesTempl reports whether the backend renders the UI itself with templ
+// and htmx.
+func (c Config) UsesTempl() bool {
+	return c.Frontend == "templ-htmx"
+}
 
-	if err := runBunCreateVite(cfg.ProjectName, "frontend"); err != nil {
-		return fmt.Errorf("bun create vite: %w", err)
+// WithDefaults returns c with the optional fields that are unset filled in:
+// the module path, port and layout.
+func (c Config) WithDefaults() Config {
+	if c.ModulePath == "" {
+		c.ModulePath = DefaultModulePath(c.ProjectName)
 	}
-
-	if err := bunInstall(frontendDir); err != nil {
-		return fmt.Errorf("bun install: %w", err)
+	if c.Port == 0 {
+		c.Port = DefaultPort
+	}
+	if c.Layout == "" {
+		c.Layout = "flat"
 	}
+	return c
+}
+
+func generateFrontend(p *Plan, cfg Config) error {
+	frontendDir := "frontend"
+	pm := packageManager(cfg)
+
+	stack, withLibrary := frontendStack(cfg)
+
+	runCreateVite(p, pm, ".", frontendDir, stack.Template)
+	runInstall(p, pm, frontendDir)
 
 	// Tailwind v4 setup
-	if err := setupTailwindV4(frontendDir); err != nil {
+	if err := setupTailwindV4(p, cfg, frontendDir); err != nil {
 		return fmt.Errorf("tailwind v4 setup: %w", err)
 	}
 
-	// Only patch tsconfig and install shadcn when user selected that option
-	if cfg.Frontend == "vite-react-tailwind-shadcn" {
-		fmt.Println("  [gokozyy] calling setupShadcnManualV4...")
-		if err := setupShadcnManualV4(frontendDir); err != nil {
-			return fmt.Errorf("shadcn manual v4 setup: %w", err)
+	// Typed API client and a landing page that calls the backend
+	if err := writeAPIClient(p, cfg, frontendDir); err != nil {
+		return fmt.Errorf("api client: %w", err)
+	}
+
+	// Only patch tsconfig and add the component library when the user selected it
+	if withLibrary {
+		if err := setupComponentLibrary(p, cfg, frontendDir); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func runGoModInit(dir, modulePath string) error {
-	cmd := exec.Command("go", "mod", "init", modulePath)
-	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+func runGoModInit(p *Plan, dir, modulePath string) {
+	p.RunCreates(dir, path.Join(dir, "go.mod"), "", "go", "mod", "init", modulePath)
+	// Match the Dockerfile's golang image rather than the local toolchain.
+	p.RunGo(dir, "", "mod", "edit", "-go="+goVersion)
 }
 
-func writeStdMain(dir string) error {
-	code := `package main
-
-import (
-	"fmt"
-	"log"
-	"net/http"
-)
-
-func main() {
-	mux := http.NewServeMux()
+func generateBackend(p *Plan, cfg Config) error {
+	backendDir := buildLayout(cfg).ModuleDir
 
-	mux.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprint(w, ` + "`" + `{"status":"ok"}` + "`" + `)
-	})
-
-	addr := ":8080"
-	log.Println("Starting standard-library server on", addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
-		log.Fatal(err)
-	}
-}
-`
-	return os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), 0o644)
-}
+	// 1) Create backend directory
+	p.Mkdir(backendDir)
 
-func generateBackend(cfg Config) error {
-	backendDir := filepath.Join(cfg.ProjectName, "backend")
+	// 2) Initialize go module
+	runGoModInit(p, backendDir, cfg.ModulePath)
 
-	// 1) Create backend directory
-	if err := os.MkdirAll(backendDir, 0o755); err != nil {
-		return fmt.Errorf("create backend dir: %w", err)
+	// 3) Typed config package read from the environment
+	if err := writeAppConfig(p, cfg, backendDir); err != nil {
+		return fmt.Errorf("config package: %w", err)
 	}
 
-	// 2) Initialize go module
-	modulePath := fmt.Sprintf("github.com/you/%s/backend", cfg.ProjectName)
-	if err := runGoModInit(backendDir, modulePath); err != nil {
-		return fmt.Errorf("go mod init: %w", err)
+	// 4) main.go based on framework
+	if err := writeMain(p, cfg, backendDir); err != nil {
+		return err
 	}
 
-	// 3) main.go based on framework
-	switch cfg.Framework {
-	case "chi":
-		if err := writeChiMain(backendDir); err != nil {
-			return err
+	// 5) UI served by the backend: the embedded frontend or templ pages
+	switch {
+	case cfg.EmbedFrontend:
+		if err := writeWebPackage(p, cfg); err != nil {
+			return fmt.Errorf("web package: %w", err)
 		}
-	case "gin":
-		if err := writeGinMain(backendDir); err != nil {
-			return err
-		}
-	default:
-		if err := writeStdMain(backendDir); err != nil {
-			return err
+	case cfg.UsesTempl():
+		if err := writeTemplUI(p, cfg); err != nil {
+			return fmt.Errorf("templ UI: %w", err)
 		}
 	}
 
-	// 4) DB scaffolding (internal/database + driver imports)
-	if err := setupDatabase(cfg, backendDir); err != nil {
+	// 6) DB scaffolding (internal/database + driver imports)
+	if err := setupDatabase(p, cfg, backendDir); err != nil {
 		return fmt.Errorf("database setup: %w", err)
 	}
 
-	// 5) .env + .gitignore at project root
-	if err := writeEnvFile(cfg); err != nil {
+	// 7) Resolve the imports of everything above
+	if err := resolveDependencies(p, cfg, backendDir); err != nil {
+		return fmt.Errorf("dependencies: %w", err)
+	}
+
+	// 8) go.work, .env + .gitignore at project root. go.work comes after
+	// the go commands so they run in plain module mode.
+	if err := writeGoWork(p, cfg); err != nil {
+		return fmt.Errorf("go.work: %w", err)
+	}
+	if err := writeEnvFile(p, cfg); err != nil {
 		return fmt.Errorf(".env: %w", err)
 	}
-	if err := writeGitignore(cfg); err != nil {
+	if err := writeGitignore(p, cfg); err != nil {
 		return fmt.Errorf(".gitignore: %w", err)
 	}
 
-	// 6) makefile
-	if err := writeMakefile(cfg); err != nil {
+	// 9) makefile
+	if err := writeMakefile(p, cfg); err != nil {
 		return fmt.Errorf("makefile: %w", err)
 	}
 
-	// NEW: 7) Write Air config for hot reloading
-	if err := writeAirConfig(cfg); err != nil {
+	// 10) Air config for hot reloading
+	if err := writeAirConfig(p, cfg); err != nil {
 		return fmt.Errorf("air config: %w", err)
 	}
 
-	// 8) Optional Docker files
+	// 11) Optional Docker files
 	if cfg.UseDocker {
-		if err := writeDockerFiles(cfg, backendDir); err != nil {
+		if err := writeDockerFiles(p, cfg); err != nil {
 			return fmt.Errorf("docker: %w", err)
 		}
 	}
 	return nil
 }
 
-// Generate is the main entry point called from cmd/create.go.
-func Generate(cfg Config) error {
+// BuildPlan works out every file, command and patch needed for cfg without
+// touching disk.
+func BuildPlan(cfg Config) (*Plan, error) {
+	cfg = cfg.WithDefaults()
+	p := &Plan{Root: cfg.ProjectName}
+
 	// Top-level project directory (same as project name for now).
-	if err := os.MkdirAll(cfg.ProjectName, 0o755); err != nil {
-		return fmt.Errorf("create project dir: %w", err)
+	p.Mkdir(".")
+
+	// 1) Scaffold backend.
+	if err := generateBackend(p, cfg); err != nil {
+		return nil, fmt.Errorf("backend: %w", err)
 	}
 
-	// 1) Scaffold backend (TODO: your templates go here).
-	if err := generateBackend(cfg); err != nil {
-		return fmt.Errorf("backend: %w", err)
+	// 2) Scaffold the Vite frontend with the chosen package manager,
+	// unless the project is API-only.
+	if cfg.HasFrontend() {
+		if err := generateFrontend(p, cfg); err != nil {
+			return nil, fmt.Errorf("frontend: %w", err)
+		}
 	}
 
-	// 2) Scaffold frontend using Bun + Vite React.
-	if err := generateFrontend(cfg); err != nil {
-		return fmt.Errorf("frontend: %w", err)
+	// 3) Record what was generated.
+	if err := writeManifest(p, cfg, nil); err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
 	}
 
-	return nil
+	return p, nil
+}
+
+// ExistMode says what Generate does when the project directory already
+// exists and is not empty.
+type ExistMode int
+
+const (
+	// ExistAbort refuses to touch a non-empty directory.
+	ExistAbort ExistMode = iota
+	// ExistForce overwrites generated files.
+	ExistForce
+	// ExistMerge only writes files that are missing and reports the ones
+	// that differ as conflicts. The manifest and base snapshots are
+	// rewritten to record only what gokozyy actually wrote.
+	ExistMerge
+)
+
+// ErrTargetExists is returned in ExistAbort mode when the project
+// directory is not empty.
+var ErrTargetExists = errors.New("project directory already exists and is not empty")
+
+// DirNotEmpty reports whether dir exists and contains anything.
+func DirNotEmpty(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// Options controls where and how a plan is executed.
+type Options struct {
+	// FS is the target filesystem, rooted at the project directory.
+	// Defaults to DiskFS{Root: cfg.ProjectName}.
+	FS FS
+	// SkipCommands skips external commands (go mod init, bun, ...) and
+	// patches to files only those commands would have created. Use it to
+	// generate into a MemFS.
+	SkipCommands bool
+	// Runner executes external commands. Defaults to an ExecRunner that
+	// streams output to the terminal. With a non-disk FS, commands see
+	// their Dir relative to the project root.
+	Runner Runner
+	// KeepOnFailure leaves the partially generated project on disk when a
+	// step fails, for debugging, instead of rolling it back.
+	KeepOnFailure bool
+	// Offline resolves Go dependencies from the module cache (or a local
+	// file:// GOPROXY) only, with GOFLAGS=-mod=mod.
+	Offline bool
+	// Existing selects abort, force or merge for a non-empty project dir.
+	// Commands whose output already exists are skipped in force and
+	// merge mode.
+	Existing ExistMode
+	// Progress receives a line per step as it starts, e.g. "◦ Tidying
+	// go.mod...". Nil discards them.
+	Progress io.Writer
+	// CommandTimeout bounds each command the default Runner runs. Zero
+	// means DefaultCommandTimeout.
+	CommandTimeout time.Duration
+}
+
+// progressf writes a progress line to o.Progress, if set.
+func (o Options) progressf(format string, args ...any) {
+	if o.Progress != nil {
+		fmt.Fprintf(o.Progress, format+"\n", args...)
+	}
+}
+
+// Generate is the main entry point called from cmd/create.go.
+func Generate(ctx context.Context, cfg Config, opts Options) (*Report, error) {
+	p, err := BuildPlan(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// A caller-supplied FS owns its own cleanup.
+	if opts.FS != nil {
+		return p.Execute(ctx, opts)
+	}
+
+	if opts.Existing == ExistAbort && DirNotEmpty(p.Root) {
+		return nil, fmt.Errorf("%w: %s", ErrTargetExists, p.Root)
+	}
+	return executeStaged(ctx, p, opts)
 }
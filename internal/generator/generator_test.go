@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files under testdata")
+
+func TestConfigWithDefaults(t *testing.T) {
+	got := Config{ProjectName: "demo"}.WithDefaults()
+	if got.Port != DefaultPort {
+		t.Errorf("Port = %d, want %d", got.Port, DefaultPort)
+	}
+	if got.Layout != "flat" {
+		t.Errorf("Layout = %q, want flat", got.Layout)
+	}
+	if got.ModulePath != DefaultModulePath("demo") {
+		t.Errorf("ModulePath = %q, want %q", got.ModulePath, DefaultModulePath("demo"))
+	}
+
+	set := Config{ProjectName: "demo", ModulePath: "example.com/x", Port: 3000, Layout: "standard"}
+	if got := set.WithDefaults(); got != set {
+		t.Errorf("WithDefaults changed set fields: %+v", got)
+	}
+}
+
+// TestGenerateGolden generates projects into a MemFS and compares every
+// file, and the commands that would have run, with testdata/generate.
+// Run `go test -run TestGenerateGolden -update` after changing a template.
+func TestGenerateGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{
+			name: "std",
+			cfg:  Config{Framework: "std", DBDriver: "none", Frontend: "none"},
+		},
+		{
+			name: "chi-postgres",
+			cfg: Config{Framework: "chi", Layout: "standard", DBDriver: "postgres",
+				Frontend: "vite-vue-tailwind-shadcn", Runtime: "bun", UseDocker: true},
+		},
+		{
+			name: "gin-sqlite",
+			cfg: Config{Framework: "gin", DBDriver: "sqlite",
+				Frontend: "vite-react-tailwind", Runtime: "npm", UseDocker: true, EmbedFrontend: true},
+		},
+		{
+			name: "echo",
+			cfg:  Config{Framework: "echo", Layout: "standard", DBDriver: "none", Frontend: "templ-htmx", UseDocker: true},
+		},
+		{
+			name: "fiber",
+			cfg:  Config{Framework: "fiber", DBDriver: "none", Frontend: "vite-svelte-tailwind", Runtime: "pnpm", Port: 3000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.ProjectName = "demo"
+			cfg.ModulePath = "example.com/demo/backend"
+
+			fsys := NewMemFS()
+			runner := &FakeRunner{Handler: seedCommandOutput(t, fsys)}
+			if _, err := Generate(context.Background(), cfg, Options{FS: fsys, Runner: runner}); err != nil {
+				t.Fatal(err)
+			}
+
+			var b strings.Builder
+			for _, name := range fsys.Files() {
+				if strings.HasPrefix(name, BaseDir+"/") {
+					continue // copies of the files below
+				}
+				data, _ := fsys.ReadFile(name)
+				info, _ := fsys.Stat(name)
+				fmt.Fprintf(&b, "-- %s (%04o) --\n%s", name, info.Mode().Perm(), data)
+			}
+			b.WriteString("-- commands --\n")
+			for _, c := range runner.Calls() {
+				fmt.Fprintf(&b, "[%s] %s\n", c.Dir, c)
+			}
+
+			golden := filepath.Join("testdata", "generate", tt.name+".golden")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, []byte(b.String()), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if got := b.String(); got != string(want) {
+				t.Errorf("generated project differs from %s at:\n%s", golden, firstDiff(got, string(want)))
+			}
+		})
+	}
+}
+
+// seedCommandOutput stands in for the files `go mod init` and create-vite
+// produce, so the patches that follow them have something to edit.
+func seedCommandOutput(t *testing.T, fsys *MemFS) func(Command) (Result, error) {
+	write := func(name, content string) {
+		if err := fsys.MkdirAll(path.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsys.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func(c Command) (Result, error) {
+		args := c.String()
+		switch {
+		case strings.HasPrefix(args, "go mod init "):
+			write(path.Join(c.Dir, "go.mod"), "module "+c.Args[2]+"\n")
+		case strings.Contains(args, "--template "):
+			template := c.Args[len(c.Args)-1]
+			for _, s := range FrontendStacks {
+				if s.Template == template {
+					dir := path.Join(c.Dir, "frontend")
+					write(path.Join(dir, "package.json"), "{}\n")
+					write(path.Join(dir, "tsconfig.json"), "{}\n")
+					write(path.Join(dir, "tsconfig.app.json"), "{}\n")
+					write(path.Join(dir, "src", s.Entry), "import App from \"./App\";\n")
+				}
+			}
+		}
+		return Result{}, nil
+	}
+}
+
+// firstDiff returns the first line that differs between got and want,
+// with the line number.
+func firstDiff(got, want string) string {
+	g, w := strings.Split(got, "\n"), strings.Split(want, "\n")
+	for i := 0; i < len(g) || i < len(w); i++ {
+		var gl, wl string
+		if i < len(g) {
+			gl = g[i]
+		}
+		if i < len(w) {
+			wl = w[i]
+		}
+		if gl != wl {
+			return fmt.Sprintf("line %d:\n  got:  %q\n  want: %q", i+1, gl, wl)
+		}
+	}
+	return "(no difference)"
+}
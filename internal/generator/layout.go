@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"path"
+)
+
+// tailwindCLI is the standalone Tailwind CLI release the templ UI's
+// Makefile and Dockerfile download.
+const tailwindCLI = "v4.1.11"
+
+// htmxVersion is the htmx release the templ UI's Makefile and Dockerfile
+// vendor into its static/ directory.
+const htmxVersion = "2.0.6"
+
+// goVersion is the go directive of the generated module and the tag of the
+// golang image the Dockerfile builds with; the two must agree.
+const goVersion = "1.24"
+
+// BuildLayout is the one build model of a generated project. The Makefile,
+// Air config, Dockerfile and go.work are all rendered from it.
+type BuildLayout struct {
+	// ModuleDir holds go.mod, relative to the project root.
+	ModuleDir string
+	// MainPkg is the main package, relative to ModuleDir.
+	MainPkg string
+	// Binary is the build output, relative to the project root.
+	Binary string
+	// GoVersion is the go directive and the golang image tag.
+	GoVersion string
+	// WebPkg is the package the backend serves the UI from, relative to
+	// ModuleDir: the embedded frontend build or the templ components.
+	// Empty for a separate Vite dev server or an API-only project.
+	WebPkg string
+	// TailwindCLI is the standalone Tailwind CLI version that builds the
+	// templ UI's stylesheet; empty unless Config.UsesTempl.
+	TailwindCLI string
+	// HTMX is the htmx version served from the templ UI's static/
+	// directory; empty unless Config.UsesTempl.
+	HTMX string
+}
+
+// buildLayout returns the layout of the project generated for cfg.
+func buildLayout(cfg Config) BuildLayout {
+	l := BuildLayout{
+		ModuleDir: "backend",
+		MainPkg:   ".",
+		Binary:    "bin/main",
+		GoVersion: goVersion,
+	}
+	if cfg.Layout == "standard" {
+		l.MainPkg = "cmd/api"
+	}
+	if cfg.EmbedFrontend || cfg.UsesTempl() {
+		l.WebPkg = "internal/web"
+	}
+	if cfg.UsesTempl() {
+		l.TailwindCLI = tailwindCLI
+		l.HTMX = htmxVersion
+	}
+	return l
+}
+
+// MainPath is the main package as a go build argument from the project
+// root, which go.work makes part of the workspace.
+func (l BuildLayout) MainPath() string {
+	return "./" + path.Join(l.ModuleDir, l.MainPkg)
+}
+
+// MainPkgArg is the main package as a go build argument from ModuleDir.
+func (l BuildLayout) MainPkgArg() string {
+	if pkg := path.Clean(l.MainPkg); pkg != "." {
+		return "./" + pkg
+	}
+	return "."
+}
+
+// BinaryDir is the directory the binary is built into.
+func (l BuildLayout) BinaryDir() string {
+	return path.Dir(l.Binary)
+}
+
+// WebDir is WebPkg relative to the project root.
+func (l BuildLayout) WebDir() string {
+	return path.Join(l.ModuleDir, l.WebPkg)
+}
+
+// EmbedDir is where the frontend build is copied for go:embed, relative
+// to the project root.
+func (l BuildLayout) EmbedDir() string {
+	return path.Join(l.WebDir(), "dist")
+}
+
+// writeGoWork plans the go.work that lets go commands run from the project
+// root.
+func writeGoWork(p *Plan, cfg Config) error {
+	return p.WriteTemplate("go.work", "go.work.tmpl", cfg, 0o644)
+}
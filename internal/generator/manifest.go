@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+)
+
+// ManifestFile is written at the root of every generated project.
+const ManifestFile = ".gokozyy.json"
+
+// BaseDir holds a snapshot of every file as gokozyy generated it, the
+// common ancestor `gokozyy upgrade` merges against.
+const BaseDir = ".gokozyy/base"
+
+// Version is the gokozyy release recorded in manifests. Set it at build
+// time with -ldflags "-X github.com/kozykoding/gokozyy/internal/generator.Version=v1.2.3".
+var Version = "dev"
+
+// Manifest records how a project was generated so later commands can
+// inspect, upgrade or extend it.
+type Manifest struct {
+	Version string          `json:"gokozyy_version"`
+	Config  Config          `json:"config"`
+	Files   []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry is one generated file and the hash of the content
+// gokozyy wrote.
+type ManifestEntry struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeManifest records every file the plan writes so far on top of the
+// entries of prev, which is nil for a new project. It must be the last
+// step added to the plan.
+func writeManifest(p *Plan, cfg Config, prev *Manifest) error {
+	var generated []Op
+	for _, op := range p.Ops {
+		if op.Kind == OpWrite {
+			generated = append(generated, op)
+		}
+	}
+	return recordGenerated(p, cfg, prev, generated)
+}
+
+// recordGenerated adds steps that snapshot each generated file under
+// BaseDir and write the manifest. generated holds write ops carrying the
+// content gokozyy rendered, which may differ from what the plan writes
+// when an upgrade merges in user changes.
+func recordGenerated(p *Plan, cfg Config, prev *Manifest, generated []Op) error {
+	m := Manifest{Version: Version, Config: cfg}
+	index := map[string]int{}
+	if prev != nil {
+		for _, f := range prev.Files {
+			index[f.Path] = len(m.Files)
+			m.Files = append(m.Files, f)
+		}
+	}
+	for _, op := range generated {
+		p.Write(path.Join(BaseDir, op.Path), op.Content, op.Mode)
+
+		entry := ManifestEntry{Path: op.Path, SHA256: hashContent(op.Content)}
+		if i, ok := index[op.Path]; ok {
+			m.Files[i] = entry
+			continue
+		}
+		index[op.Path] = len(m.Files)
+		m.Files = append(m.Files, entry)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	p.Write(ManifestFile, append(data, '\n'), 0o644)
+	return nil
+}
+
+// mergeManifest adjusts the manifest data a merge into an existing
+// directory writes: files left as conflicts keep their entry from the
+// manifest already there, prev, if any, and are dropped otherwise.
+func mergeManifest(data, prev []byte, conflicts []string) ([]byte, error) {
+	if len(conflicts) == 0 {
+		return data, nil
+	}
+	var m, old Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if prev != nil {
+		// An unreadable old manifest just has no entries to keep.
+		_ = json.Unmarshal(prev, &old)
+	}
+
+	files := m.Files[:0]
+	for _, f := range m.Files {
+		if !slices.Contains(conflicts, f.Path) {
+			files = append(files, f)
+		} else if e, ok := old.Entry(f.Path); ok {
+			files = append(files, e)
+		}
+	}
+	m.Files = files
+
+	out, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// ReadManifest loads the manifest of the project in dir.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ManifestFile, err)
+	}
+	return &m, nil
+}
+
+// Entry returns the manifest entry for path, if gokozyy generated it.
+func (m *Manifest) Entry(path string) (ManifestEntry, bool) {
+	for _, f := range m.Files {
+		if f.Path == path {
+			return f, true
+		}
+	}
+	return ManifestEntry{}, false
+}
+
+// ReadBase returns the snapshot of path as gokozyy last generated it, or
+// nil if the project has none.
+func ReadBase(dir, file string) []byte {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(BaseDir), filepath.FromSlash(file)))
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,178 @@
+package generator
+
+import (
+	"strings"
+)
+
+// splitLines splits s into lines that keep their trailing newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// matchLines returns, for each line of a, the index of the line of b it is
+// paired with in a longest common subsequence, or -1.
+func matchLines(a, b []string) []int {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	match := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) {
+		switch {
+		case j < len(b) && a[i] == b[j]:
+			match[i] = j
+			i++
+			j++
+		case j < len(b) && lcs[i][j+1] >= lcs[i+1][j]:
+			j++
+		default:
+			match[i] = -1
+			i++
+		}
+	}
+	return match
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeHunk is one region of a three-way merge.
+type mergeHunk struct {
+	base, ours, theirs []string
+	conflict           bool
+	// resolved is the merged text of a non-conflicting hunk.
+	resolved []string
+}
+
+// merge3 performs a line-based three-way merge of ours and theirs against
+// their common ancestor base, in the style of diff3.
+func merge3(base, ours, theirs string) []mergeHunk {
+	b, o, t := splitLines(base), splitLines(ours), splitLines(theirs)
+	mo, mt := matchLines(b, o), matchLines(b, t)
+
+	var hunks []mergeHunk
+	i, j, k := 0, 0, 0
+	for i < len(b) || j < len(o) || k < len(t) {
+		// Stable line: unchanged on both sides.
+		if i < len(b) && mo[i] == j && mt[i] == k {
+			hunks = append(hunks, mergeHunk{resolved: []string{b[i]}})
+			i, j, k = i+1, j+1, k+1
+			continue
+		}
+
+		// Find the next base line both sides kept, ending this chunk.
+		n, oe, te := len(b), len(o), len(t)
+		for x := i; x < len(b); x++ {
+			if mo[x] >= j && mt[x] >= k {
+				n, oe, te = x, mo[x], mt[x]
+				break
+			}
+		}
+
+		h := mergeHunk{base: b[i:n], ours: o[j:oe], theirs: t[k:te]}
+		switch {
+		case equalLines(h.ours, h.base):
+			h.resolved = h.theirs
+		case equalLines(h.theirs, h.base), equalLines(h.ours, h.theirs):
+			h.resolved = h.ours
+		default:
+			h.conflict = true
+		}
+		hunks = append(hunks, h)
+		i, j, k = n, oe, te
+	}
+	return hunks
+}
+
+// withMarkers renders hunks with git-style conflict markers around the
+// conflicting regions.
+func withMarkers(hunks []mergeHunk, oursLabel, theirsLabel string) string {
+	var sb strings.Builder
+	writeLines := func(lines []string) {
+		for _, l := range lines {
+			sb.WriteString(l)
+			if !strings.HasSuffix(l, "\n") {
+				sb.WriteString("\n")
+			}
+		}
+	}
+	for _, h := range hunks {
+		if !h.conflict {
+			for _, l := range h.resolved {
+				sb.WriteString(l)
+			}
+			continue
+		}
+		sb.WriteString("<<<<<<< " + oursLabel + "\n")
+		writeLines(h.ours)
+		sb.WriteString("=======\n")
+		writeLines(h.theirs)
+		sb.WriteString(">>>>>>> " + theirsLabel + "\n")
+	}
+	return sb.String()
+}
+
+// oursWithRejects keeps our side of every conflict and returns the
+// rejected template changes separately, one hunk per conflict.
+func oursWithRejects(hunks []mergeHunk, file string) (merged, rejects string) {
+	var out, rej strings.Builder
+	for _, h := range hunks {
+		if !h.conflict {
+			for _, l := range h.resolved {
+				out.WriteString(l)
+			}
+			continue
+		}
+		for _, l := range h.ours {
+			out.WriteString(l)
+		}
+		if rej.Len() == 0 {
+			rej.WriteString("--- " + file + "\n+++ " + file + " (gokozyy template)\n")
+		}
+		rej.WriteString("@@ rejected hunk @@\n")
+		for _, l := range h.base {
+			rej.WriteString("-" + strings.TrimSuffix(l, "\n") + "\n")
+		}
+		for _, l := range h.theirs {
+			rej.WriteString("+" + strings.TrimSuffix(l, "\n") + "\n")
+		}
+	}
+	return out.String(), rej.String()
+}
+
+func hasConflict(hunks []mergeHunk) bool {
+	for _, h := range hunks {
+		if h.conflict {
+			return true
+		}
+	}
+	return false
+}
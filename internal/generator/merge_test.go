@@ -0,0 +1,82 @@
+package generator
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		conflict           bool
+	}{
+		{
+			name: "unchanged",
+			base: "a\nb\nc\n", ours: "a\nb\nc\n", theirs: "a\nb\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "only ours changed",
+			base: "a\nb\nc\n", ours: "a\nB\nc\n", theirs: "a\nb\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "only theirs changed",
+			base: "a\nb\nc\n", ours: "a\nb\nc\n", theirs: "a\nb\nC\n",
+			want: "a\nb\nC\n",
+		},
+		{
+			name: "separate edits",
+			base: "a\nb\nc\nd\ne\n", ours: "A\nb\nc\nd\ne\n", theirs: "a\nb\nc\nd\nE\n",
+			want: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name: "same edit on both sides",
+			base: "a\nb\nc\n", ours: "a\nX\nc\n", theirs: "a\nX\nc\n",
+			want: "a\nX\nc\n",
+		},
+		{
+			name: "insertions and deletions",
+			base: "a\nb\nc\nd\n", ours: "a\nnew\nb\nc\nd\n", theirs: "a\nb\nc\n",
+			want: "a\nnew\nb\nc\n",
+		},
+		{
+			name: "empty base",
+			base: "", ours: "x\n", theirs: "x\n",
+			want: "x\n",
+		},
+		{
+			name: "overlapping edits",
+			base: "a\nb\nc\n", ours: "a\nours\nc\n", theirs: "a\ntheirs\nc\n",
+			want:     "a\n<<<<<<< yours\nours\n=======\ntheirs\n>>>>>>> gokozyy\nc\n",
+			conflict: true,
+		},
+		{
+			name: "missing final newline",
+			base: "a\nb", ours: "a\nb", theirs: "a\nc",
+			want: "a\nc",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks := merge3(tt.base, tt.ours, tt.theirs)
+			if got := hasConflict(hunks); got != tt.conflict {
+				t.Errorf("hasConflict = %v, want %v", got, tt.conflict)
+			}
+			if got := withMarkers(hunks, "yours", "gokozyy"); got != tt.want {
+				t.Errorf("merged =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOursWithRejects(t *testing.T) {
+	hunks := merge3("a\nb\nc\n", "a\nours\nc\n", "a\ntheirs\nc\n")
+	merged, rej := oursWithRejects(hunks, "main.go")
+	if want := "a\nours\nc\n"; merged != want {
+		t.Errorf("merged = %q, want %q", merged, want)
+	}
+	want := "--- main.go\n+++ main.go (gokozyy template)\n@@ rejected hunk @@\n-b\n+theirs\n"
+	if rej != want {
+		t.Errorf("rejects = %q, want %q", rej, want)
+	}
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fallbackModulePrefix is used when neither the user config file nor git
+// say who owns the module.
+const fallbackModulePrefix = "github.com/you"
+
+// UserDefaults is the optional per-user config file at
+// $XDG_CONFIG_HOME/gokozyy/config.json (see os.UserConfigDir).
+type UserDefaults struct {
+	// ModulePrefix is prepended to the project name to build the Go module
+	// path, e.g. "github.com/acme" or "gitlab.example.com/team".
+	ModulePrefix string `json:"module_prefix"`
+}
+
+// LoadUserDefaults reads the user config file. A missing file is not an
+// error.
+func LoadUserDefaults() (UserDefaults, error) {
+	var d UserDefaults
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return d, nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "gokozyy", "config.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return d, err
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return d, fmt.Errorf("parse gokozyy config: %w", err)
+	}
+	return d, nil
+}
+
+// DefaultModulePath suggests the backend module path for a project under
+// ModulePrefix.
+func DefaultModulePath(projectName string) string {
+	return BackendModulePath(ModulePrefix(), projectName)
+}
+
+// BackendModulePath is the backend module path of a project under prefix.
+func BackendModulePath(prefix, projectName string) string {
+	return prefix + "/" + projectName + "/backend"
+}
+
+// ModulePrefix is who owns new modules: the user config file's
+// module_prefix, then `git config github.user`, then github.com/you. It
+// may run git, so callers that need it repeatedly should keep the result.
+func ModulePrefix() string {
+	if d, err := LoadUserDefaults(); err == nil && d.ModulePrefix != "" {
+		return strings.TrimSuffix(d.ModulePrefix, "/")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	r := &ExecRunner{}
+	res, err := r.Run(ctx, Command{Name: "git", Args: []string{"config", "--get", "github.user"}})
+	if user := strings.TrimSpace(string(res.Stdout)); err == nil && user != "" {
+		return "github.com/" + user
+	}
+
+	return fallbackModulePrefix
+}
+
+// ValidateModulePath rejects paths `go mod init` would refuse.
+func ValidateModulePath(p string) error {
+	switch {
+	case p == "":
+		return fmt.Errorf("module path is required")
+	case strings.HasPrefix(p, "/") || strings.HasSuffix(p, "/"):
+		return fmt.Errorf("module path %q must not start or end with a slash", p)
+	case strings.Contains(p, "//"):
+		return fmt.Errorf("module path %q has an empty element", p)
+	}
+	for _, elem := range strings.Split(p, "/") {
+		if elem == "." || elem == ".." {
+			return fmt.Errorf("module path %q must not contain %q", p, elem)
+		}
+		if strings.HasPrefix(elem, ".") || strings.HasSuffix(elem, ".") {
+			return fmt.Errorf("module path element %q must not start or end with a dot", elem)
+		}
+	}
+	for _, r := range p {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-._~/", r)) {
+			return fmt.Errorf("module path %q contains invalid character %q", p, r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// maxProjectNameLen matches the wizard's input limit.
+const maxProjectNameLen = 64
+
+// NormalizeProjectName applies the safe, unsurprising fixes: surrounding
+// whitespace is trimmed, letters are lowercased and inner whitespace
+// becomes "-". Anything else is left for ValidateProjectName to reject.
+func NormalizeProjectName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Join(strings.Fields(name), "-")
+}
+
+// ValidateProjectName reports why name cannot be used. The name becomes a
+// directory, a Go module path element and the Docker compose project and
+// container name, so it must be lowercase letters, digits, "-" or "_",
+// starting with a letter or digit.
+func ValidateProjectName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("project name is required")
+	case len(name) > maxProjectNameLen:
+		return fmt.Errorf("project name is %d characters; the limit is %d", len(name), maxProjectNameLen)
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("project name %q must not contain path separators; it is created in the current directory", name)
+	case strings.Contains(name, ".."):
+		return fmt.Errorf("project name %q must not contain \"..\"", name)
+	}
+
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case unicode.IsUpper(r):
+			return fmt.Errorf("project name %q must be lowercase (Docker compose project names are)", name)
+		case unicode.IsSpace(r):
+			return fmt.Errorf("project name %q must not contain spaces", name)
+		case (r == '-' || r == '_') && i > 0:
+		case r == '-' || r == '_':
+			return fmt.Errorf("project name %q must start with a letter or digit", name)
+		default:
+			return fmt.Errorf("project name %q contains %q; use a-z, 0-9, \"-\" or \"_\"", name, r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeProjectName(t *testing.T) {
+	tests := map[string]string{
+		"my-app":        "my-app",
+		"  My App  ":    "my-app",
+		"My\tCool  App": "my-cool-app",
+		"api_v2":        "api_v2",
+	}
+	for in, want := range tests {
+		if got := NormalizeProjectName(in); got != want {
+			t.Errorf("NormalizeProjectName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValidateProjectName(t *testing.T) {
+	valid := []string{"my-app", "app2", "2fast", "api_v2", strings.Repeat("a", maxProjectNameLen)}
+	for _, name := range valid {
+		if err := ValidateProjectName(name); err != nil {
+			t.Errorf("ValidateProjectName(%q) = %v, want nil", name, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		strings.Repeat("a", maxProjectNameLen+1),
+		"a/b",
+		`a\b`,
+		"..",
+		"a..b",
+		"MyApp",
+		"my app",
+		"-app",
+		"_app",
+		"my.app",
+		"café",
+	}
+	for _, name := range invalid {
+		if err := ValidateProjectName(name); err == nil {
+			t.Errorf("ValidateProjectName(%q) = nil, want an error", name)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	ok := Config{
+		ProjectName: "demo",
+		Framework:   "std",
+		DBDriver:    "none",
+		Frontend:    "vite-react-tailwind",
+		Runtime:     "bun",
+	}
+	if err := ok.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   string // substring of the error; empty means valid
+	}{
+		{"bad name", func(c *Config) { c.ProjectName = "My App" }, "project name"},
+		{"bad module", func(c *Config) { c.ModulePath = "not a module" }, "module"},
+		{"port out of range", func(c *Config) { c.Port = 70000 }, "port"},
+		{"unknown framework", func(c *Config) { c.Framework = "rails" }, "framework"},
+		{"unknown layout", func(c *Config) { c.Layout = "nested" }, "layout"},
+		{"unknown db", func(c *Config) { c.DBDriver = "mysql" }, "db"},
+		{"unknown frontend", func(c *Config) { c.Frontend = "angular" }, "frontend"},
+		{"unknown runtime", func(c *Config) { c.Runtime = "jsr" }, "runtime"},
+		{"embed without vite", func(c *Config) { c.Frontend, c.EmbedFrontend = "none", true }, "embed-frontend"},
+		{"api only ignores runtime", func(c *Config) { c.Frontend, c.Runtime = "none", "" }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := ok
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			switch {
+			case tt.want == "":
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+			case err == nil:
+				t.Errorf("Validate() = nil, want an error")
+			case !strings.Contains(err.Error(), tt.want):
+				t.Errorf("Validate() = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Option is one selectable value for a Config field. The wizard renders
+// these as radio options and the CLI validates flags against them.
+type Option struct {
+	Value       string
+	Label       string
+	Description string
+}
+
+// DBOptions are the supported database drivers.
+var DBOptions = []Option{
+	{
+		Value:       "none",
+		Label:       "None",
+		Description: "No DB driver will be installed",
+	},
+	{
+		Value:       "postgres",
+		Label:       "Postgres",
+		Description: "pgx postgres driver for Go",
+	},
+	{
+		Value:       "sqlite",
+		Label:       "Sqlite",
+		Description: "sqlite3 driver for Go's database/sql interface",
+	},
+}
+
+// LayoutOptions are the supported backend source layouts.
+var LayoutOptions = []Option{
+	{
+		Value:       "flat",
+		Label:       "Flat",
+		Description: "Everything in backend/main.go",
+	},
+	{
+		Value:       "standard",
+		Label:       "Standard",
+		Description: "cmd/api, internal/server, internal/handlers and internal/database wired together",
+	},
+}
+
+// FrontendOptions are the supported frontend stacks.
+var FrontendOptions = frontendOptions()
+
+// RuntimeOptions are the supported JavaScript runtimes / package managers.
+var RuntimeOptions = packageManagerOptions()
+
+// Validate checks every Config field against the option sets above.
+func (c Config) Validate() error {
+	if err := ValidateProjectName(c.ProjectName); err != nil {
+		return err
+	}
+	if c.ModulePath != "" {
+		if err := ValidateModulePath(c.ModulePath); err != nil {
+			return err
+		}
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("port %d is out of range (1-65535)", c.Port)
+	}
+	if err := checkOption("framework", c.Framework, FrameworkOptions()); err != nil {
+		return err
+	}
+	if c.Layout != "" {
+		if err := checkOption("layout", c.Layout, LayoutOptions); err != nil {
+			return err
+		}
+	}
+	if err := checkOption("db", c.DBDriver, DBOptions); err != nil {
+		return err
+	}
+	if err := checkOption("frontend", c.Frontend, FrontendOptions); err != nil {
+		return err
+	}
+	if !c.HasFrontend() {
+		if c.EmbedFrontend {
+			return fmt.Errorf("embed-frontend needs a Vite frontend, not %q", c.Frontend)
+		}
+		// No Vite app, so no JavaScript runtime to check.
+		return nil
+	}
+	if err := checkOption("runtime", c.Runtime, RuntimeOptions); err != nil {
+		return err
+	}
+	return nil
+}
+
+func checkOption(field, value string, opts []Option) error {
+	for _, o := range opts {
+		if o.Value == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q (valid: %s)", field, value, OptionValues(opts))
+}
+
+// OptionValues lists the values of opts, comma separated.
+func OptionValues(opts []Option) string {
+	values := make([]string, len(opts))
+	for i, o := range opts {
+		values[i] = o.Value
+	}
+	return strings.Join(values, ", ")
+}
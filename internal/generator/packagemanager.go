@@ -0,0 +1,195 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// PackageManager is one JavaScript runtime / package manager option. The
+// generator runs its commands to scaffold the frontend, and the Dockerfile,
+// Makefile and next steps are rendered from it.
+type PackageManager struct {
+	ID          string
+	Label       string
+	Description string
+
+	create  []string // scaffolds a package: followed by name and create-vite flags
+	flagSep bool     // create needs "--" before create-vite's own flags
+	install []string
+	add     []string
+	addDev  []string
+	run     []string // followed by the script name
+	runSep  bool     // run needs "--" before the script's own flags
+	// pkgPrefix is prepended to package names, e.g. Deno's "npm:".
+	pkgPrefix string
+
+	// Lockfiles are the lockfile names the manager writes.
+	Lockfiles []string
+	// Image is the Docker base image with the runtime installed.
+	Image string
+	// DockerSetup, if set, is a RUN step needed before install.
+	DockerSetup string
+}
+
+// PackageManagers are the supported runtimes, in wizard order.
+var PackageManagers = []PackageManager{
+	{
+		ID:          "bun",
+		Label:       "Bun",
+		Description: "Bun runtime and package manager",
+		create:      []string{"bunx", "create-vite@latest"},
+		install:     []string{"bun", "install"},
+		add:         []string{"bun", "add"},
+		addDev:      []string{"bun", "add", "-D"},
+		run:         []string{"bun", "run"},
+		Lockfiles:   []string{"bun.lock", "bun.lockb"},
+		Image:       "oven/bun:latest",
+	},
+	{
+		ID:          "npm",
+		Label:       "npm",
+		Description: "Node.js with npm",
+		create:      []string{"npm", "create", "--yes", "vite@latest"},
+		flagSep:     true,
+		install:     []string{"npm", "install"},
+		add:         []string{"npm", "install"},
+		addDev:      []string{"npm", "install", "-D"},
+		run:         []string{"npm", "run"},
+		runSep:      true,
+		Lockfiles:   []string{"package-lock.json"},
+		Image:       "node:22-alpine",
+	},
+	{
+		ID:          "pnpm",
+		Label:       "pnpm",
+		Description: "Node.js with pnpm (via corepack)",
+		create:      []string{"pnpm", "create", "vite@latest"},
+		install:     []string{"pnpm", "install"},
+		add:         []string{"pnpm", "add"},
+		addDev:      []string{"pnpm", "add", "-D"},
+		run:         []string{"pnpm", "run"},
+		Lockfiles:   []string{"pnpm-lock.yaml"},
+		Image:       "node:22-alpine",
+		DockerSetup: "corepack enable",
+	},
+	{
+		ID:          "yarn",
+		Label:       "Yarn",
+		Description: "Node.js with Yarn",
+		create:      []string{"yarn", "create", "vite"},
+		install:     []string{"yarn", "install"},
+		add:         []string{"yarn", "add"},
+		addDev:      []string{"yarn", "add", "-D"},
+		run:         []string{"yarn", "run"},
+		Lockfiles:   []string{"yarn.lock"},
+		Image:       "node:22-alpine",
+	},
+	{
+		ID:          "deno",
+		Label:       "Deno",
+		Description: "Deno 2 with its npm compatibility",
+		create:      []string{"deno", "run", "-A", "npm:create-vite@latest"},
+		install:     []string{"deno", "install"},
+		add:         []string{"deno", "add"},
+		addDev:      []string{"deno", "add", "-D"},
+		run:         []string{"deno", "task"},
+		pkgPrefix:   "npm:",
+		Lockfiles:   []string{"deno.lock"},
+		Image:       "denoland/deno:latest",
+	},
+}
+
+// LookupPackageManager returns the package manager with the given ID.
+func LookupPackageManager(id string) (PackageManager, bool) {
+	for _, pm := range PackageManagers {
+		if pm.ID == id {
+			return pm, true
+		}
+	}
+	return PackageManager{}, false
+}
+
+// packageManager returns cfg's package manager, defaulting to Bun for
+// configs written before the runtime was selectable.
+func packageManager(cfg Config) PackageManager {
+	if pm, ok := LookupPackageManager(cfg.Runtime); ok {
+		return pm
+	}
+	return PackageManagers[0]
+}
+
+func packageManagerOptions() []Option {
+	opts := make([]Option, len(PackageManagers))
+	for i, pm := range PackageManagers {
+		opts[i] = Option{Value: pm.ID, Label: pm.Label, Description: pm.Description}
+	}
+	return opts
+}
+
+// CreateViteArgs scaffolds a create-vite template into name.
+func (pm PackageManager) CreateViteArgs(name, template string) []string {
+	args := append(append([]string(nil), pm.create...), name)
+	if pm.flagSep {
+		args = append(args, "--")
+	}
+	return append(args, "--template", template)
+}
+
+// InstallArgs installs the dependencies in package.json.
+func (pm PackageManager) InstallArgs() []string {
+	return append([]string(nil), pm.install...)
+}
+
+// AddArgs adds packages as dependencies, or devDependencies when dev is
+// set.
+func (pm PackageManager) AddArgs(dev bool, pkgs ...string) []string {
+	args := append([]string(nil), pm.add...)
+	if dev {
+		args = append([]string(nil), pm.addDev...)
+	}
+	for _, pkg := range pkgs {
+		args = append(args, pm.pkgPrefix+pkg)
+	}
+	return args
+}
+
+// RunArgs runs a package.json script with extra arguments for it.
+func (pm PackageManager) RunArgs(script string, extra ...string) []string {
+	args := append(append([]string(nil), pm.run...), script)
+	if pm.runSep && len(extra) > 0 {
+		args = append(args, "--")
+	}
+	return append(args, extra...)
+}
+
+// Run is RunArgs as a shell command line, for the Makefile and docs.
+func (pm PackageManager) Run(script string, extra ...string) string {
+	return strings.Join(pm.RunArgs(script, extra...), " ")
+}
+
+// Install is InstallArgs as a shell command line.
+func (pm PackageManager) Install() string {
+	return strings.Join(pm.install, " ")
+}
+
+// RunJSON is RunArgs in Dockerfile exec form.
+func (pm PackageManager) RunJSON(script string, extra ...string) string {
+	args := pm.RunArgs(script, extra...)
+	for i, a := range args {
+		args[i] = strconv.Quote(a)
+	}
+	return "[" + strings.Join(args, ", ") + "]"
+}
+
+// runCreateVite scaffolds a Vite app from template into dir/name.
+func runCreateVite(p *Plan, pm PackageManager, dir, name, template string) {
+	p.RunCreates(dir, path.Join(dir, name), fmt.Sprintf("Scaffolding frontend in %s", name),
+		pm.CreateViteArgs(name, template)...)
+}
+
+// runInstall installs the frontend's dependencies in dir.
+func runInstall(p *Plan, pm PackageManager, dir string) {
+	p.Run(dir, "", pm.InstallArgs()...)
+}
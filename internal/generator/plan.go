@@ -0,0 +1,442 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// OpKind identifies what a plan step does.
+type OpKind string
+
+const (
+	OpMkdir OpKind = "mkdir"
+	OpWrite OpKind = "write"
+	OpRun   OpKind = "run"
+	OpPatch OpKind = "patch"
+)
+
+// PatchFunc rewrites the current content of a file. data is nil when the
+// file does not exist and the patch is optional.
+type PatchFunc func(data []byte) ([]byte, error)
+
+// Op is one step of a Plan. Path and Dir are slash-separated and relative
+// to the project root.
+type Op struct {
+	Kind        OpKind
+	Path        string       // mkdir, write, patch
+	Mode        fs.FileMode  // mkdir, write, optional patch of a missing file
+	Content     []byte       // write
+	Dir         string       // run
+	Args        []string     // run
+	Creates     string       // run: path the command produces, for rollback
+	Requires    []Dependency // run: `go get` targets; ones go.mod already requires are left out
+	Uses        string       // run: existing file the command rewrites or relies on; merge mode skips it if kept
+	Description string
+	Optional    bool // patch: a missing file is passed to Patch as nil
+	Patch       PatchFunc
+}
+
+func (op Op) String() string {
+	switch op.Kind {
+	case OpRun:
+		return "run " + strings.Join(op.Args, " ")
+	default:
+		return string(op.Kind) + " " + op.Path
+	}
+}
+
+// MarshalJSON renders the op for `create --dry-run --format json`.
+func (op Op) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Kind        OpKind   `json:"kind"`
+		Path        string   `json:"path,omitempty"`
+		Mode        string   `json:"mode,omitempty"`
+		Size        *int     `json:"size,omitempty"`
+		Dir         string   `json:"dir,omitempty"`
+		Args        []string `json:"args,omitempty"`
+		Description string   `json:"description,omitempty"`
+	}{
+		Kind:        op.Kind,
+		Path:        op.Path,
+		Dir:         op.Dir,
+		Args:        op.Args,
+		Description: op.Description,
+	}
+	switch op.Kind {
+	case OpMkdir:
+		out.Mode = fmt.Sprintf("%04o", op.Mode.Perm())
+	case OpWrite:
+		out.Mode = fmt.Sprintf("%04o", op.Mode.Perm())
+		size := len(op.Content)
+		out.Size = &size
+	}
+	return json.Marshal(out)
+}
+
+// Plan is the ordered list of steps that generate a project. Building a
+// plan has no side effects; Execute applies it.
+type Plan struct {
+	Root string `json:"root"` // project directory, relative to the working dir
+	Ops  []Op   `json:"ops"`
+}
+
+// Mkdir adds a directory creation step.
+func (p *Plan) Mkdir(dir string) {
+	p.Ops = append(p.Ops, Op{Kind: OpMkdir, Path: dir, Mode: 0o755})
+}
+
+// Write adds a file write step. Parent directories are created as needed.
+func (p *Plan) Write(file string, content []byte, mode fs.FileMode) {
+	p.Ops = append(p.Ops, Op{Kind: OpWrite, Path: file, Mode: mode, Content: content})
+}
+
+// WriteTemplate renders the named template and adds it as a write step.
+func (p *Plan) WriteTemplate(file, name string, cfg Config, mode fs.FileMode) error {
+	data, err := renderTemplate(name, cfg)
+	if err != nil {
+		return err
+	}
+	p.Write(file, data, mode)
+	return nil
+}
+
+// Run adds an external command step executed in dir.
+func (p *Plan) Run(dir, description string, args ...string) {
+	p.Ops = append(p.Ops, Op{Kind: OpRun, Dir: dir, Args: args, Description: description})
+}
+
+// RunCreates is Run for commands that produce a known path, such as the
+// directory `create-vite` scaffolds, so a failed run can remove it.
+func (p *Plan) RunCreates(dir, creates, description string, args ...string) {
+	p.Ops = append(p.Ops, Op{Kind: OpRun, Dir: dir, Args: args, Creates: creates, Description: description})
+}
+
+// GoGet adds a `go get` of deps run in dir. At run time modules that dir's
+// go.mod already requires are left out, so a version the user picked is
+// never lowered, and the step is skipped when nothing is missing.
+func (p *Plan) GoGet(dir, description string, deps []Dependency) {
+	args := []string{"go", "get"}
+	for _, d := range deps {
+		args = append(args, d.String())
+	}
+	p.Ops = append(p.Ops, Op{Kind: OpRun, Dir: dir, Args: args, Requires: deps, Uses: path.Join(dir, "go.mod"), Description: description})
+}
+
+// RunGo is Run for go commands that work on dir's module: they rewrite
+// go.mod and go.sum or use the tools it declares. Merge mode skips them
+// when it kept an existing go.mod, leaving the module as the user has it.
+func (p *Plan) RunGo(dir, description string, args ...string) {
+	p.Ops = append(p.Ops, Op{Kind: OpRun, Dir: dir, Args: append([]string{"go"}, args...), Uses: path.Join(dir, "go.mod"), Description: description})
+}
+
+// Patch adds a step that rewrites an existing file in place.
+func (p *Plan) Patch(file, description string, fn PatchFunc) {
+	p.Ops = append(p.Ops, Op{Kind: OpPatch, Path: file, Description: description, Patch: fn})
+}
+
+// PatchOptional is Patch for files that may not exist yet; one the patch
+// creates gets mode.
+func (p *Plan) PatchOptional(file, description string, mode fs.FileMode, fn PatchFunc) {
+	p.Ops = append(p.Ops, Op{Kind: OpPatch, Path: file, Mode: mode, Description: description, Patch: fn, Optional: true})
+}
+
+// Report summarizes what Execute did beyond the plain happy path.
+type Report struct {
+	// Skipped lists steps left out because their target already existed.
+	Skipped []string
+	// Conflicts lists existing files whose content differs from what the
+	// plan would write; merge mode leaves them untouched.
+	Conflicts []string
+}
+
+// Execute applies every step of the plan to opts.FS, in order.
+func (p *Plan) Execute(ctx context.Context, opts Options) (*Report, error) {
+	e := &executor{opts: opts, fsys: opts.FS, runner: opts.Runner, report: &Report{}}
+	if e.fsys == nil {
+		e.fsys = DiskFS{Root: p.Root}
+	}
+	if e.runner == nil {
+		timeout := opts.CommandTimeout
+		if timeout == 0 {
+			timeout = DefaultCommandTimeout
+		}
+		e.runner = &ExecRunner{Timeout: timeout, Stdout: os.Stdout, Stderr: os.Stderr}
+	}
+
+	for _, op := range p.Ops {
+		if op.Kind == OpRun && opts.SkipCommands {
+			continue
+		}
+		if err := e.execute(ctx, op); err != nil {
+			return e.report, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	return e.report, nil
+}
+
+type executor struct {
+	opts   Options
+	fsys   FS
+	runner Runner
+	report *Report
+	// kept holds outputs of commands that were skipped because they
+	// already existed; patches beneath them are skipped too.
+	kept []string
+}
+
+func (e *executor) exists(name string) bool {
+	_, err := e.fsys.Stat(name)
+	return err == nil
+}
+
+func (e *executor) underKept(name string) bool {
+	for _, k := range e.kept {
+		if name == k || strings.HasPrefix(name, k+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *executor) execute(ctx context.Context, op Op) error {
+	fsys := e.fsys
+
+	switch op.Kind {
+	case OpMkdir:
+		return fsys.MkdirAll(op.Path, op.Mode)
+	case OpWrite:
+		if e.opts.Existing == ExistMerge {
+			switch {
+			case op.Path == ManifestFile:
+				prev, _ := fsys.ReadFile(op.Path)
+				data, err := mergeManifest(op.Content, prev, e.report.Conflicts)
+				if err != nil {
+					return err
+				}
+				op.Content = data
+			case strings.HasPrefix(op.Path, BaseDir+"/"):
+				// Snapshot only files the merge wrote or found identical;
+				// a conflicting file is the user's, not what gokozyy made.
+				if slices.Contains(e.report.Conflicts, strings.TrimPrefix(op.Path, BaseDir+"/")) {
+					return nil
+				}
+			default:
+				if old, err := fsys.ReadFile(op.Path); err == nil {
+					if !bytes.Equal(old, op.Content) {
+						e.report.Conflicts = append(e.report.Conflicts, op.Path)
+					}
+					return nil
+				}
+			}
+		}
+		if err := fsys.MkdirAll(path.Dir(op.Path), 0o755); err != nil {
+			return err
+		}
+		return fsys.WriteFile(op.Path, op.Content, op.Mode)
+	case OpRun:
+		// Never re-run a scaffolding command over existing output.
+		if e.opts.Existing != ExistAbort && op.Creates != "" && e.exists(op.Creates) {
+			e.kept = append(e.kept, op.Creates)
+			e.report.Skipped = append(e.report.Skipped, op.String())
+			return nil
+		}
+		if e.opts.Existing == ExistMerge && op.Uses != "" && e.underKept(op.Uses) {
+			e.report.Skipped = append(e.report.Skipped, op.String())
+			return nil
+		}
+		args := op.Args
+		if op.Requires != nil {
+			gomod, _ := fsys.ReadFile(path.Join(op.Dir, "go.mod"))
+			missing := missingDependencies(gomod, op.Requires)
+			if len(missing) == 0 {
+				e.report.Skipped = append(e.report.Skipped, op.String())
+				return nil
+			}
+			args = []string{"go", "get"}
+			for _, d := range missing {
+				args = append(args, d.String())
+			}
+		}
+		dir := op.Dir
+		if local, ok := fsys.(localFS); ok {
+			dir = local.Path(op.Dir)
+		} else if e.opts.Runner == nil {
+			return fmt.Errorf("commands need an on-disk target (set Runner or SkipCommands)")
+		}
+		if t, ok := fsys.(interface{ track(string) }); ok && op.Creates != "" {
+			t.track(op.Creates)
+		}
+		if op.Description != "" {
+			e.opts.progressf("◦ %s...", op.Description)
+		}
+		cmd := Command{Name: args[0], Args: args[1:], Dir: dir}
+		if e.opts.Offline && cmd.Name == "go" {
+			cmd.Env = offlineGoEnv()
+		}
+		_, err := e.runner.Run(ctx, cmd)
+		return err
+	case OpPatch:
+		if e.opts.Existing == ExistMerge && e.underKept(op.Path) {
+			e.report.Skipped = append(e.report.Skipped, op.String())
+			return nil
+		}
+		mode := fs.FileMode(0o644)
+		if op.Mode != 0 {
+			mode = op.Mode
+		}
+		data, err := fsys.ReadFile(op.Path)
+		switch {
+		case err == nil:
+			if info, statErr := fsys.Stat(op.Path); statErr == nil {
+				mode = info.Mode().Perm()
+			}
+		case errors.Is(err, fs.ErrNotExist) && op.Optional:
+			data = nil
+		case errors.Is(err, fs.ErrNotExist) && e.opts.SkipCommands:
+			// The file would have come from a skipped command.
+			return nil
+		default:
+			return err
+		}
+		out, err := op.Patch(data)
+		if err != nil {
+			return err
+		}
+		if err := fsys.MkdirAll(path.Dir(op.Path), 0o755); err != nil {
+			return err
+		}
+		return fsys.WriteFile(op.Path, out, mode)
+	default:
+		return fmt.Errorf("unknown op kind %q", op.Kind)
+	}
+}
+
+// WriteJSON prints the plan as indented JSON.
+func (p *Plan) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// WriteTree prints the files the plan writes as a directory tree, followed
+// by the commands it runs and the files it patches. The snapshots under
+// BaseDir, which repeat every generated file, are summed up in one line.
+func (p *Plan) WriteTree(w io.Writer) error {
+	type entry struct {
+		dir  bool
+		size int
+		mode fs.FileMode
+		note string
+	}
+	entries := map[string]entry{}
+	addParents := func(file string) {
+		for d := path.Dir(file); d != "." && d != "/"; d = path.Dir(d) {
+			if _, ok := entries[d]; !ok {
+				entries[d] = entry{dir: true, mode: 0o755}
+			}
+		}
+	}
+
+	var commands, patches []Op
+	snapshots := 0
+	for _, op := range p.Ops {
+		switch op.Kind {
+		case OpMkdir:
+			if op.Path != "." {
+				entries[op.Path] = entry{dir: true, mode: op.Mode}
+				addParents(op.Path)
+			}
+		case OpWrite:
+			if strings.HasPrefix(op.Path, BaseDir+"/") {
+				snapshots++
+				continue
+			}
+			entries[op.Path] = entry{size: len(op.Content), mode: op.Mode}
+			addParents(op.Path)
+		case OpRun:
+			commands = append(commands, op)
+		case OpPatch:
+			patches = append(patches, op)
+			addParents(op.Path)
+		}
+	}
+
+	if snapshots > 0 {
+		entries[BaseDir] = entry{dir: true, mode: 0o755, note: fmt.Sprintf("snapshots of %d generated files", snapshots)}
+		addParents(BaseDir)
+	}
+
+	paths := make([]string, 0, len(entries))
+	for k := range entries {
+		paths = append(paths, k)
+	}
+	// Compare per path segment so "a/b" sorts before "a-b".
+	sort.Slice(paths, func(i, j int) bool {
+		return strings.ReplaceAll(paths[i], "/", "\x00") < strings.ReplaceAll(paths[j], "/", "\x00")
+	})
+
+	// last reports whether k is the final entry of its directory.
+	last := map[string]bool{}
+	for i, k := range paths {
+		last[k] = true
+		for _, next := range paths[i+1:] {
+			if path.Dir(next) == path.Dir(k) {
+				last[k] = false
+				break
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "%s/\n", p.Root)
+	for _, k := range paths {
+		var indent string
+		for d := path.Dir(k); d != "."; d = path.Dir(d) {
+			if last[d] {
+				indent = "    " + indent
+			} else {
+				indent = "│   " + indent
+			}
+		}
+
+		branch := "├── "
+		if last[k] {
+			branch = "└── "
+		}
+		e := entries[k]
+		switch {
+		case e.note != "":
+			fmt.Fprintf(w, "%s%s%s/ (%s)\n", indent, branch, path.Base(k), e.note)
+		case e.dir:
+			fmt.Fprintf(w, "%s%s%s/\n", indent, branch, path.Base(k))
+		default:
+			fmt.Fprintf(w, "%s%s%s (%d B, %04o)\n", indent, branch, path.Base(k), e.size, e.mode.Perm())
+		}
+	}
+
+	if len(commands) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Commands:")
+		for _, op := range commands {
+			fmt.Fprintf(w, "  $ %s    (in %s)\n", strings.Join(op.Args, " "), path.Join(p.Root, op.Dir))
+		}
+	}
+
+	if len(patches) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Patches:")
+		for _, op := range patches {
+			fmt.Fprintf(w, "  ~ %s    (%s)\n", op.Path, op.Description)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExecuteMergeBookkeeping(t *testing.T) {
+	cfg := Config{
+		ProjectName: "demo",
+		ModulePath:  "example.com/demo/backend",
+		Framework:   "std",
+		DBDriver:    "none",
+		Frontend:    "vite-react-tailwind",
+	}
+	p, err := BuildPlan(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := NewMemFS()
+	old := Manifest{Files: []ManifestEntry{
+		{Path: "Makefile", SHA256: "old-makefile"},
+		{Path: ".gitignore", SHA256: "old-gitignore"},
+	}}
+	oldData, _ := json.Marshal(old)
+	for name, data := range map[string]string{
+		ManifestFile:                 string(oldData),
+		BaseDir + "/Makefile":        "old snapshot\n",
+		BaseDir + "/backend/main.go": "old snapshot\n",
+		"Makefile":                   "user makefile\n",
+		"backend/main.go":            "package main // user's\n",
+	} {
+		if err := fsys.MkdirAll(path.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := fsys.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := p.Execute(context.Background(), Options{FS: fsys, SkipCommands: true, Existing: ExistMerge})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(report.Conflicts)
+	if want := []string{"Makefile", "backend/main.go"}; !slices.Equal(report.Conflicts, want) {
+		t.Errorf("Conflicts = %v, want %v", report.Conflicts, want)
+	}
+
+	m, err := readMemManifest(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A conflicting file keeps the entry it had, or has none.
+	if e, ok := m.Entry("Makefile"); !ok || e.SHA256 != "old-makefile" {
+		t.Errorf("Makefile entry = %+v, %v; want the old one", e, ok)
+	}
+	if e, ok := m.Entry("backend/main.go"); ok {
+		t.Errorf("backend/main.go has entry %+v, want none", e)
+	}
+	// Written files are recorded as generated.
+	gi, _ := fsys.ReadFile(".gitignore")
+	if e, ok := m.Entry(".gitignore"); !ok || e.SHA256 != hashContent(gi) {
+		t.Errorf(".gitignore entry = %+v, %v; want hash of the written file", e, ok)
+	}
+
+	if data, _ := fsys.ReadFile(BaseDir + "/Makefile"); string(data) != "old snapshot\n" {
+		t.Errorf("Makefile snapshot was overwritten with %q", data)
+	}
+	if data, _ := fsys.ReadFile(BaseDir + "/.gitignore"); string(data) != string(gi) {
+		t.Errorf(".gitignore snapshot = %q, want the written file", data)
+	}
+}
+
+func readMemManifest(fsys FS) (*Manifest, error) {
+	data, err := fsys.ReadFile(ManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	return &m, json.Unmarshal(data, &m)
+}
+
+func TestWriteTree(t *testing.T) {
+	p := &Plan{Root: "demo"}
+	p.Mkdir(".")
+	p.Write("a-b.txt", []byte("x"), 0o644)
+	p.Write("a/z.go", []byte("package a\n"), 0o644)
+	p.Write("a/b/c.go", []byte("package b\n"), 0o644)
+	p.Write(".env", []byte("PORT=1\n"), 0o600)
+	p.Write(BaseDir+"/a/z.go", []byte("package a\n"), 0o644)
+	p.Write(BaseDir+"/a-b.txt", []byte("x"), 0o644)
+	p.Run("a", "", "go", "mod", "tidy")
+	p.Patch("a/z.go", "add import", nil)
+
+	var b strings.Builder
+	if err := p.WriteTree(&b); err != nil {
+		t.Fatal(err)
+	}
+	// Per-segment order puts "a/" before "a-b.txt" even though '-' < '/'.
+	want := `demo/
+├── .env (7 B, 0600)
+├── .gokozyy/
+│   └── base/ (snapshots of 2 generated files)
+├── a/
+│   ├── b/
+│   │   └── c.go (10 B, 0644)
+│   └── z.go (10 B, 0644)
+└── a-b.txt (1 B, 0644)
+
+Commands:
+  $ go mod tidy    (in demo/a)
+
+Patches:
+  ~ a/z.go    (add import)
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteTree =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExecuteMergeKeepsGoModule(t *testing.T) {
+	cfg := Config{
+		ProjectName: "demo",
+		ModulePath:  "example.com/demo/backend",
+		Framework:   "gin",
+		DBDriver:    "none",
+		Frontend:    "none",
+	}
+	p, err := BuildPlan(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := NewMemFS()
+	gomod := "module example.com/demo/backend\n\ngo 1.25\n\nrequire github.com/gin-gonic/gin v1.11.0\n"
+	if err := fsys.MkdirAll("backend", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile("backend/go.mod", []byte(gomod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &FakeRunner{}
+	report, err := p.Execute(context.Background(), Options{FS: fsys, Runner: runner, Existing: ExistMerge})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := runner.Calls(); len(calls) != 0 {
+		t.Errorf("merge ran %v over an existing module", calls)
+	}
+	if !slices.Contains(report.Skipped, "run go mod tidy") {
+		t.Errorf("Skipped = %v, want go mod tidy listed", report.Skipped)
+	}
+	if data, _ := fsys.ReadFile("backend/go.mod"); string(data) != gomod {
+		t.Errorf("go.mod = %q, want it untouched", data)
+	}
+}
+
+func TestExecuteProgress(t *testing.T) {
+	p := &Plan{}
+	p.Run(".", "Tidying go.mod", "go", "mod", "tidy")
+	p.Run(".", "", "go", "mod", "edit", "-go=1.24")
+
+	var progress strings.Builder
+	opts := Options{FS: NewMemFS(), Runner: &FakeRunner{}, Progress: &progress}
+	if _, err := p.Execute(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := progress.String(), "◦ Tidying go.mod...\n"; got != want {
+		t.Errorf("progress = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// executeStaged runs p in a hidden sibling directory and renames it into
+// place only when every step succeeded, so a failed run never leaves a
+// half-built project behind. If the project directory already exists the
+// plan runs in place and whatever it created or overwrote is undone on
+// failure instead.
+func executeStaged(ctx context.Context, p *Plan, opts Options) (*Report, error) {
+	target := p.Root
+
+	if _, err := os.Stat(target); err == nil {
+		return executeTracked(ctx, p, opts)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return nil, err
+	}
+	stage, err := os.MkdirTemp(filepath.Dir(abs), "."+filepath.Base(abs)+".gokozyy-")
+	if err != nil {
+		return nil, fmt.Errorf("create staging dir: %w", err)
+	}
+	if err := os.Chmod(stage, 0o755); err != nil {
+		os.RemoveAll(stage)
+		return nil, err
+	}
+
+	opts.FS = DiskFS{Root: stage}
+	report, err := p.Execute(ctx, opts)
+	if err != nil {
+		if opts.KeepOnFailure {
+			opts.progressf("◦ Kept partial project in %s", stage)
+		} else {
+			os.RemoveAll(stage)
+		}
+		return report, err
+	}
+
+	if err := os.Rename(stage, target); err != nil {
+		os.RemoveAll(stage)
+		return report, fmt.Errorf("move project into place: %w", err)
+	}
+	return report, nil
+}
+
+// executeTracked runs p directly in its existing root directory and rolls
+// back its changes on failure.
+func executeTracked(ctx context.Context, p *Plan, opts Options) (*Report, error) {
+	t := &trackingFS{DiskFS: DiskFS{Root: p.Root}, saved: map[string]savedFile{}}
+	opts.FS = t
+
+	report, err := p.Execute(ctx, opts)
+	if err != nil {
+		if opts.KeepOnFailure {
+			opts.progressf("◦ Kept partial changes in %s", p.Root)
+			return report, err
+		}
+		if rbErr := t.rollback(); rbErr != nil {
+			return report, fmt.Errorf("%w (rollback: %v)", err, rbErr)
+		}
+		return report, err
+	}
+	return report, nil
+}
+
+// trackingFS is a DiskFS that remembers every path it creates and the
+// previous content of every file it overwrites, so it can undo them.
+type trackingFS struct {
+	DiskFS
+	created []string
+	saved   map[string]savedFile
+}
+
+type savedFile struct {
+	data []byte
+	mode fs.FileMode
+}
+
+// track records name as created by the plan if it does not exist yet. The
+// executor calls it for paths produced by external commands.
+func (t *trackingFS) track(name string) {
+	if _, err := t.DiskFS.Stat(name); errors.Is(err, fs.ErrNotExist) {
+		t.created = append(t.created, name)
+	}
+}
+
+func (t *trackingFS) MkdirAll(name string, perm fs.FileMode) error {
+	// Record the outermost missing directory; removing it removes the rest.
+	var missing string
+	for d := path.Clean(name); d != "."; d = path.Dir(d) {
+		if _, err := t.DiskFS.Stat(d); errors.Is(err, fs.ErrNotExist) {
+			missing = d
+		} else {
+			break
+		}
+	}
+	if err := t.DiskFS.MkdirAll(name, perm); err != nil {
+		return err
+	}
+	if missing != "" {
+		t.created = append(t.created, missing)
+	}
+	return nil
+}
+
+func (t *trackingFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if _, ok := t.saved[name]; !ok {
+		old, err := t.DiskFS.ReadFile(name)
+		switch {
+		case err == nil:
+			info, statErr := t.DiskFS.Stat(name)
+			if statErr != nil {
+				return statErr
+			}
+			t.saved[name] = savedFile{data: old, mode: info.Mode().Perm()}
+		case errors.Is(err, fs.ErrNotExist):
+			t.created = append(t.created, name)
+			// Mark as seen so a second write does not save our own content.
+			t.saved[name] = savedFile{}
+		default:
+			return err
+		}
+	}
+	return t.DiskFS.WriteFile(name, data, perm)
+}
+
+// rollback removes created paths (newest first) and restores overwritten
+// files.
+func (t *trackingFS) rollback() error {
+	var errs []error
+	for i := len(t.created) - 1; i >= 0; i-- {
+		if err := t.DiskFS.RemoveAll(t.created[i]); err != nil {
+			errs = append(errs, err)
+		}
+		delete(t.saved, t.created[i])
+	}
+	for name, f := range t.saved {
+		if f.data == nil {
+			continue
+		}
+		if err := t.DiskFS.WriteFile(name, f.data, f.mode); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var errFake = errors.New("fake failure")
+
+// writeFiles creates files (slash paths relative to dir) with content.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// samplePlan writes some files and then runs a command, which
+// failingRunner makes fail.
+func samplePlan(root string) *Plan {
+	p := &Plan{Root: root}
+	p.Mkdir(".")
+	p.Write("keep.txt", []byte("generated\n"), 0o644)
+	p.Write("backend/main.go", []byte("package main\n"), 0o644)
+	p.Run("backend", "", "go", "mod", "tidy")
+	return p
+}
+
+func failingRunner() *FakeRunner {
+	return &FakeRunner{Results: map[string]FakeResult{"go": {Err: errFake}}}
+}
+
+func TestExecuteStagedRollsBack(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "demo")
+
+	_, err := executeStaged(context.Background(), samplePlan(root), Options{Runner: failingRunner()})
+	if !errors.Is(err, errFake) {
+		t.Fatalf("err = %v, want %v", err, errFake)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("left %s behind", e.Name())
+	}
+}
+
+func TestExecuteStagedKeepOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "demo")
+
+	_, err := executeStaged(context.Background(), samplePlan(root), Options{Runner: failingRunner(), KeepOnFailure: true})
+	if !errors.Is(err, errFake) {
+		t.Fatalf("err = %v, want %v", err, errFake)
+	}
+	stages, _ := filepath.Glob(filepath.Join(dir, ".demo.gokozyy-*", "backend", "main.go"))
+	if len(stages) != 1 {
+		t.Errorf("partial project not kept: %v", stages)
+	}
+	if _, err := os.Stat(root); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("failed project moved into place: %v", err)
+	}
+}
+
+func TestExecuteStagedMovesIntoPlace(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "demo")
+
+	if _, err := executeStaged(context.Background(), samplePlan(root), Options{Runner: &FakeRunner{}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "backend", "main.go")); err != nil {
+		t.Error(err)
+	}
+	if stages, _ := filepath.Glob(filepath.Join(dir, ".demo.gokozyy-*")); len(stages) > 0 {
+		t.Errorf("staging dir left behind: %v", stages)
+	}
+}
+
+func TestExecuteTrackedRestoresExisting(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"keep.txt": "mine\n"})
+	if err := os.Chmod(filepath.Join(root, "keep.txt"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := executeTracked(context.Background(), samplePlan(root), Options{Runner: failingRunner()})
+	if !errors.Is(err, errFake) {
+		t.Fatalf("err = %v, want %v", err, errFake)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "keep.txt"))
+	if err != nil || string(data) != "mine\n" {
+		t.Errorf("keep.txt = %q, %v; want it restored", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(root, "keep.txt")); err == nil && info.Mode().Perm() != 0o600 {
+		t.Errorf("keep.txt mode = %04o, want 0600", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(root, "backend")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("backend/ not removed: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Command is one external program invocation.
+type Command struct {
+	Name string
+	Args []string
+	Dir  string   // working directory
+	Env  []string // KEY=VALUE entries layered over the runner's environment
+}
+
+func (c Command) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// Result is what a finished command produced.
+type Result struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// Runner executes the external tools the generator depends on (go, bun, ...).
+type Runner interface {
+	Run(ctx context.Context, cmd Command) (Result, error)
+}
+
+// DefaultCommandTimeout bounds each command Execute runs when
+// Options.CommandTimeout is unset; a hung install should not hang create.
+const DefaultCommandTimeout = 10 * time.Minute
+
+// ExecRunner runs commands with os/exec, capturing their output.
+type ExecRunner struct {
+	// Timeout bounds each command; zero means no limit beyond ctx.
+	Timeout time.Duration
+	// Env overrides applied to every command, on top of os.Environ().
+	Env []string
+	// Stdout and Stderr receive live output as well as the capture in
+	// Result. Nil discards it.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+func (r *ExecRunner) Run(ctx context.Context, c Command) (Result, error) {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	cmd.Stdout = teeWriter(&stdout, r.Stdout)
+	cmd.Stderr = teeWriter(&stderr, r.Stderr)
+	if len(r.Env) > 0 || len(c.Env) > 0 {
+		cmd.Env = append(append(os.Environ(), r.Env...), c.Env...)
+	}
+
+	err := cmd.Run()
+	res := Result{Stdout: stdout.Bytes(), Stderr: stderr.Bytes()}
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	switch {
+	case err == nil:
+		return res, nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return res, fmt.Errorf("timed out after %s", r.Timeout)
+	case ctx.Err() != nil:
+		return res, ctx.Err()
+	default:
+		return res, err
+	}
+}
+
+func teeWriter(capture *bytes.Buffer, live io.Writer) io.Writer {
+	if live == nil {
+		return capture
+	}
+	return io.MultiWriter(capture, live)
+}
+
+// FakeResult is a scripted outcome for FakeRunner.
+type FakeResult struct {
+	Result Result
+	Err    error
+}
+
+// FakeRunner records every command and returns scripted results without
+// running anything, so generation can be exercised with no bun or network.
+type FakeRunner struct {
+	// Results is keyed by the full command line ("bun add -D tailwindcss")
+	// or just the program name ("bunx"); the full line wins.
+	Results map[string]FakeResult
+	// Handler, when set, is called for commands with no scripted result.
+	// It can seed files a real tool would have produced.
+	Handler func(Command) (Result, error)
+
+	mu    sync.Mutex
+	calls []Command
+}
+
+func (f *FakeRunner) Run(ctx context.Context, c Command) (Result, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, c)
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	if r, ok := f.Results[c.String()]; ok {
+		return r.Result, r.Err
+	}
+	if r, ok := f.Results[c.Name]; ok {
+		return r.Result, r.Err
+	}
+	if f.Handler != nil {
+		return f.Handler(c)
+	}
+	return Result{}, nil
+}
+
+// Calls returns the commands run so far, in order.
+func (f *FakeRunner) Calls() []Command {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Command(nil), f.calls...)
+}
@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").ParseFS(templateFS,
+	"templates/backend/*.tmpl",
+	"templates/project/*.tmpl",
+	"templates/frontend/*.tmpl",
+))
+
+// DefaultPort is what the generated backend listens on when Config.Port
+// is unset.
+const DefaultPort = 8080
+
+// templateData is what every template is rendered with.
+type templateData struct {
+	Config
+	Build BuildLayout
+	PM    PackageManager
+	Stack FrontendStack
+}
+
+func newTemplateData(cfg Config) templateData {
+	if cfg.Port == 0 {
+		cfg.Port = DefaultPort
+	}
+	stack, _ := frontendStack(cfg)
+	return templateData{
+		Config: cfg,
+		Build:  buildLayout(cfg),
+		PM:     packageManager(cfg),
+		Stack:  stack,
+	}
+}
+
+// renderTemplate executes the named template (its file name, e.g.
+// "Makefile.tmpl") with cfg.
+func renderTemplate(name string, cfg Config) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, newTemplateData(cfg)); err != nil {
+		return nil, fmt.Errorf("render %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictStyle selects how Upgrade records changes it cannot merge.
+type ConflictStyle string
+
+const (
+	// ConflictMarkers writes <<<<<<< / ======= / >>>>>>> into the file.
+	ConflictMarkers ConflictStyle = "markers"
+	// ConflictReject keeps the user's side and writes the rejected
+	// template hunks to <file>.rej.
+	ConflictReject ConflictStyle = "rej"
+)
+
+// UpgradeRequest describes one `gokozyy upgrade` run.
+type UpgradeRequest struct {
+	Dir      string
+	Conflict ConflictStyle
+	// DryRun computes the result without writing anything.
+	DryRun bool
+}
+
+// UpgradeResult says what happened to each template-managed file.
+type UpgradeResult struct {
+	Updated    []string // untouched by the user; replaced with the new template
+	Merged     []string // user and template changes merged cleanly
+	Conflicted []string // needs manual resolution
+	Added      []string // new in this gokozyy version
+	Kept       []string // user deleted it; left deleted
+}
+
+// Upgrade re-renders the templates for the project's recorded options and
+// three-way merges them with the user's files, using the snapshots under
+// BaseDir as the common ancestor.
+func Upgrade(ctx context.Context, req UpgradeRequest, opts Options) (*UpgradeResult, error) {
+	cfg, m, err := LoadProject(req.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if req.Conflict == "" {
+		req.Conflict = ConflictMarkers
+	}
+
+	fresh, err := BuildPlan(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &UpgradeResult{}
+	p := &Plan{Root: req.Dir}
+	var generated []Op
+
+	for _, op := range fresh.Ops {
+		if op.Kind != OpWrite || op.Path == ManifestFile || strings.HasPrefix(op.Path, BaseDir+"/") {
+			continue
+		}
+
+		cur, err := os.ReadFile(filepath.Join(req.Dir, filepath.FromSlash(op.Path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			if m != nil {
+				if _, ok := m.Entry(op.Path); ok {
+					res.Kept = append(res.Kept, op.Path)
+					continue
+				}
+			}
+			res.Added = append(res.Added, op.Path)
+			p.Write(op.Path, op.Content, op.Mode)
+			generated = append(generated, op)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		generated = append(generated, op)
+
+		base := ReadBase(req.Dir, op.Path)
+		if base == nil && m != nil {
+			// No snapshot, but the file is provably as generated.
+			if e, ok := m.Entry(op.Path); ok && e.SHA256 == hashContent(cur) {
+				base = cur
+			}
+		}
+
+		switch {
+		case bytes.Equal(cur, op.Content):
+			continue
+		case base != nil && bytes.Equal(base, op.Content):
+			continue // template unchanged; keep the user's edits
+		case base != nil && bytes.Equal(cur, base):
+			res.Updated = append(res.Updated, op.Path)
+			p.Write(op.Path, op.Content, op.Mode)
+			continue
+		}
+
+		hunks := merge3(string(base), string(cur), string(op.Content))
+		if !hasConflict(hunks) {
+			res.Merged = append(res.Merged, op.Path)
+			p.Write(op.Path, []byte(withMarkers(hunks, "", "")), op.Mode)
+			continue
+		}
+
+		res.Conflicted = append(res.Conflicted, op.Path)
+		switch req.Conflict {
+		case ConflictReject:
+			merged, rej := oursWithRejects(hunks, op.Path)
+			p.Write(op.Path, []byte(merged), op.Mode)
+			p.Write(op.Path+".rej", []byte(rej), 0o644)
+		default:
+			p.Write(op.Path, []byte(withMarkers(hunks, "yours", "gokozyy "+Version)), op.Mode)
+		}
+	}
+
+	if req.DryRun {
+		return res, nil
+	}
+
+	if err := recordGenerated(p, cfg, m, generated); err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+
+	opts.Existing = ExistForce
+	if opts.FS != nil {
+		_, err = p.Execute(ctx, opts)
+	} else {
+		_, err = executeTracked(ctx, p, opts)
+	}
+	return res, err
+}
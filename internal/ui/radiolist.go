@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kozykoding/gokozyy/internal/generator"
 )
 
 // RadioOption holds one selectable item.
@@ -13,6 +14,15 @@ type RadioOption struct {
 	Value       string
 }
 
+// radioOptions converts generator options into radio list items.
+func radioOptions(opts []generator.Option) []RadioOption {
+	out := make([]RadioOption, len(opts))
+	for i, o := range opts {
+		out[i] = RadioOption{Label: o.Label, Description: o.Description, Value: o.Value}
+	}
+	return out
+}
+
 // RadioListModel manages a list where exactly 0 or 1 item is selected.
 type RadioListModel struct {
 	Title    string
@@ -38,6 +38,9 @@ var (
 			Foreground(lipgloss.Color("#666666")).
 			MarginTop(1)
 
+	WarnStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#ffaf00"))
+
 	BoxStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("#00ffd7")).
@@ -2,19 +2,25 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kozykoding/gokozyy/internal/generator"
 )
 
 // Steps
 const (
 	stepName = iota
+	stepModule
 	stepFramework
+	stepLayout
 	stepDB
-	stepDocker // NEW
+	stepDocker
 	stepFrontend
+	stepRuntime
+	stepEmbed
 	stepSummary
 	stepDone
 )
@@ -22,20 +28,35 @@ const (
 // Result is what the wizard will return.
 type Result struct {
 	ProjectName string
-	Framework   string // backend: std|chi|gin
+	ModulePath  string // Go module path for backend/
+	Framework   string // backend: a registered generator.Framework ID
+	Layout      string // flat|standard
 	DBDriver    string // none|postgres|sqlite
-	Frontend    string // "vite-react-tailwind" or "vite-react-tailwind-shadcn"
-	Runtime     string // set to "bun"
+	Frontend    string // one of generator.FrontendOptions
+	Runtime     string // JS package manager: bun|npm|pnpm|yarn|deno
 	UseDocker   bool
-	Confirmed   bool
+	Port        int // backend port; the wizard keeps generator.DefaultPort
+	// EmbedFrontend serves the built frontend from the Go binary.
+	EmbedFrontend bool
+	Confirmed     bool
 }
 
 type WizardModel struct {
-	step          int
-	nameInput     textinput.Model
+	step         int
+	nameInput    textinput.Model
+	moduleInput  textinput.Model
+	modulePrefix string // generator.ModulePrefix, looked up once
+	moduleErr    string
+	nameErr      string
+	// checkedName is the project name last checked on disk; nameTaken
+	// says whether that directory exists and is not empty.
+	checkedName   string
+	nameTaken     bool
 	frameworkList RadioListModel
+	layoutList    RadioListModel
 	dbList        RadioListModel
 	frontendList  RadioListModel
+	runtimeList   RadioListModel
 	result        Result
 	quit          bool
 }
@@ -48,65 +69,32 @@ func NewWizardModel() WizardModel {
 	ti.CharLimit = 64
 	ti.Width = 30
 
-	frontendOpts := []RadioOption{
-		{
-			Label: "Vite + React + Tailwind + Bun",
-			Description: "Basic Vite React app styled with Tailwind CSS, " +
-				"using Bun as the runtime",
-			Value: "vite-react-tailwind",
-		},
-		{
-			Label: "Vite + React + Tailwind + shadcn/ui + Bun",
-			Description: "Vite React app with Tailwind and shadcn/ui components " +
-				"(with Bun runtime)",
-			Value: "vite-react-tailwind-shadcn",
-		},
-	}
-
-	frameworkOpts := []RadioOption{
-		{
-			Label:       "Standard-library",
-			Description: "The built-in Go standard library HTTP package",
-			Value:       "std",
-		},
-		{
-			Label:       "Chi",
-			Description: "Lightweight, idiomatic router for Go HTTP services",
-			Value:       "chi",
-		},
-		{
-			Label:       "Gin",
-			Description: "Martini-like API, high performance HTTP framework",
-			Value:       "gin",
-		},
-	}
+	mi := textinput.New()
+	mi.CharLimit = 256
+	mi.Width = 50
 
-	dbOpts := []RadioOption{
-		{
-			Label:       "None",
-			Description: "No DB driver will be installed",
-			Value:       "none",
-		},
-		{
-			Label:       "Postgres",
-			Description: "pgx postgres driver for Go",
-			Value:       "postgres",
-		},
-		{
-			Label:       "Sqlite",
-			Description: "sqlite3 driver for Go's database/sql interface",
-			Value:       "sqlite",
-		},
-	}
+	frontendOpts := radioOptions(generator.FrontendOptions)
+	frameworkOpts := radioOptions(generator.FrameworkOptions())
+	layoutOpts := radioOptions(generator.LayoutOptions)
+	dbOpts := radioOptions(generator.DBOptions)
+	runtimeOpts := radioOptions(generator.RuntimeOptions)
 
 	return WizardModel{
-		step:      stepName,
-		nameInput: ti,
+		step:         stepName,
+		nameInput:    ti,
+		moduleInput:  mi,
+		modulePrefix: generator.ModulePrefix(),
+		result:       Result{Port: generator.DefaultPort},
 		frameworkList: NewRadioList(
 			"What framework do you want to use in your Go project?",
 			"Press y to confirm choice.",
 			frameworkOpts,
 		),
+		layoutList: NewRadioList(
+			"How do you want the backend code laid out?",
+			"Press y to confirm choice.",
+			layoutOpts,
+		),
 		dbList: NewRadioList(
 			"What database driver do you want to use in your Go project?",
 			"Press y to confirm choice.",
@@ -117,6 +105,11 @@ func NewWizardModel() WizardModel {
 			"Press y to confirm choice.",
 			frontendOpts,
 		),
+		runtimeList: NewRadioList(
+			"Which JavaScript runtime / package manager should the frontend use?",
+			"Press y to confirm choice.",
+			runtimeOpts,
+		),
 	}
 }
 
@@ -124,11 +117,32 @@ func (m WizardModel) Init() tea.Cmd {
 	return textinput.Blink
 }
 
+// dirCheckedMsg reports whether the directory for a project name exists
+// and is not empty.
+type dirCheckedMsg struct {
+	name     string
+	notEmpty bool
+}
+
+// checkDir looks at the project directory off the render path.
+func checkDir(name string) tea.Cmd {
+	return func() tea.Msg {
+		return dirCheckedMsg{name: name, notEmpty: generator.DirNotEmpty(name)}
+	}
+}
+
 func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case dirCheckedMsg:
+		// Drop results for a name the user has typed past.
+		if msg.name == m.checkedName {
+			m.nameTaken = msg.notEmpty
+		}
+		return m, nil
 	case tea.KeyMsg:
-		// global keys
-		if msg.String() == "ctrl+c" || msg.String() == "q" {
+		// global keys; "q" is a regular character while typing
+		typing := m.step == stepName || m.step == stepModule
+		if msg.String() == "ctrl+c" || msg.String() == "q" && !typing || msg.Type == tea.KeyEsc && typing {
 			m.quit = true
 			return m, tea.Quit
 		}
@@ -136,14 +150,22 @@ func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch m.step {
 		case stepName:
 			return m.updateName(msg)
+		case stepModule:
+			return m.updateModule(msg)
 		case stepFramework:
 			return m.updateFramework(msg)
+		case stepLayout:
+			return m.updateLayout(msg)
 		case stepDB:
 			return m.updateDB(msg)
 		case stepDocker:
 			return m.updateDocker(msg)
 		case stepFrontend:
 			return m.updateFrontend(msg)
+		case stepRuntime:
+			return m.updateRuntime(msg)
+		case stepEmbed:
+			return m.updateEmbed(msg)
 		case stepSummary:
 			return m.updateSummary(msg)
 		case stepDone:
@@ -153,7 +175,11 @@ func (m WizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Let components update (e.g. blinking cursor)
 	var cmd tea.Cmd
-	m.nameInput, cmd = m.nameInput.Update(msg)
+	if m.step == stepModule {
+		m.moduleInput, cmd = m.moduleInput.Update(msg)
+	} else {
+		m.nameInput, cmd = m.nameInput.Update(msg)
+	}
 	return m, cmd
 }
 
@@ -164,11 +190,46 @@ func (m WizardModel) updateName(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// keep focus but maybe set default
 			m.nameInput.SetValue("my-project")
 		}
+		name := generator.NormalizeProjectName(m.nameInput.Value())
+		if err := generator.ValidateProjectName(name); err != nil {
+			m.nameErr = err.Error()
+			return m, nil
+		}
+		m.nameErr = ""
+		m.nameInput.SetValue(name)
+		m.moduleInput.SetValue(generator.BackendModulePath(m.modulePrefix, name))
+		m.moduleInput.CursorEnd()
+		m.nameInput.Blur()
+		m.step = stepModule
+		return m, m.moduleInput.Focus()
+	}
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	if name := generator.NormalizeProjectName(m.nameInput.Value()); name != m.checkedName {
+		m.checkedName, m.nameTaken = name, false
+		if name != "" {
+			cmd = tea.Batch(cmd, checkDir(name))
+		}
+	}
+	return m, cmd
+}
+
+func (m WizardModel) updateModule(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		v := strings.TrimSpace(m.moduleInput.Value())
+		if err := generator.ValidateModulePath(v); err != nil {
+			m.moduleErr = err.Error()
+			return m, nil
+		}
+		m.moduleErr = ""
+		m.result.ModulePath = v
+		m.moduleInput.Blur()
 		m.step = stepFramework
 		return m, nil
 	}
 	var cmd tea.Cmd
-	m.nameInput, cmd = m.nameInput.Update(msg)
+	m.moduleInput, cmd = m.moduleInput.Update(msg)
 	return m, cmd
 }
 
@@ -177,7 +238,7 @@ func (m WizardModel) updateFramework(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "y":
 		if v, ok := m.frameworkList.SelectedValue(); ok {
 			m.result.Framework = v
-			m.step = stepDB
+			m.step = stepLayout
 			return m, nil
 		}
 		// if nothing selected, ignore
@@ -187,13 +248,31 @@ func (m WizardModel) updateFramework(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m WizardModel) updateLayout(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		if v, ok := m.layoutList.SelectedValue(); ok {
+			m.result.Layout = v
+			m.step = stepDB
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.layoutList, cmd = m.layoutList.Update(msg)
+	return m, cmd
+}
+
 func (m WizardModel) updateFrontend(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "y":
 		if v, ok := m.frontendList.SelectedValue(); ok {
 			m.result.Frontend = v
-			m.result.Runtime = "bun"
-			m.step = stepSummary
+			m.step = stepRuntime
+			if !(generator.Config{Frontend: v}).HasFrontend() {
+				// API only or templ: no runtime to pick and nothing to embed.
+				m.result.EmbedFrontend = false
+				m.step = stepSummary
+			}
 			return m, nil
 		}
 	}
@@ -202,6 +281,20 @@ func (m WizardModel) updateFrontend(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m WizardModel) updateRuntime(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		if v, ok := m.runtimeList.SelectedValue(); ok {
+			m.result.Runtime = v
+			m.step = stepEmbed
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.runtimeList, cmd = m.runtimeList.Update(msg)
+	return m, cmd
+}
+
 func (m WizardModel) updateDB(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "y":
@@ -233,6 +326,23 @@ func (m WizardModel) updateDocker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m WizardModel) updateEmbed(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.result.EmbedFrontend = true
+		m.step = stepSummary
+		return m, nil
+	case "n":
+		m.result.EmbedFrontend = false
+		m.step = stepSummary
+		return m, nil
+	case "h", "left":
+		m.step = stepRuntime
+		return m, nil
+	}
+	return m, nil
+}
+
 func (m WizardModel) updateSummary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter", "y":
@@ -240,12 +350,14 @@ func (m WizardModel) updateSummary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.result.ProjectName == "" {
 			m.result.ProjectName = "my-project"
 		}
-		// runtime is already set when frontend was chosen
 		m.result.Confirmed = true
 		m.step = stepDone
 		return m, tea.Quit
 	case "h", "left":
-		m.step = stepFrontend
+		m.step = stepEmbed
+		if !(generator.Config{Frontend: m.result.Frontend}).HasFrontend() {
+			m.step = stepFrontend
+		}
 	}
 	return m, nil
 }
@@ -254,14 +366,22 @@ func (m WizardModel) View() string {
 	switch m.step {
 	case stepName:
 		return m.viewName()
+	case stepModule:
+		return m.viewModule()
 	case stepFramework:
 		return m.frameworkList.View()
+	case stepLayout:
+		return m.layoutList.View()
 	case stepDB:
 		return m.dbList.View()
 	case stepDocker:
 		return m.viewDocker()
 	case stepFrontend:
 		return m.frontendList.View()
+	case stepRuntime:
+		return m.runtimeList.View()
+	case stepEmbed:
+		return m.viewEmbed()
 	case stepSummary:
 		return m.viewSummary()
 	case stepDone:
@@ -282,14 +402,50 @@ func (m WizardModel) viewDocker() string {
 	return BoxStyle.Render(body)
 }
 
+func (m WizardModel) viewEmbed() string {
+	body := fmt.Sprintf(
+		"%s\n%s\n\n%s\n\n%s",
+		TitleStyle.Render("Single binary"),
+		QuestionStyle.Render("Embed the built frontend in the Go binary?"),
+		"Press y for Yes, n for No.",
+		HelpStyle.Render("y = yes • n = no • h = back • q = quit"),
+	)
+	return BoxStyle.Render(body)
+}
+
 func (m WizardModel) viewName() string {
 	body := fmt.Sprintf(
 		"%s\n%s\n\n%s\n\n%s",
 		TitleStyle.Render(Banner),
 		QuestionStyle.Render("What is the name of your project?"),
 		m.nameInput.View(),
-		HelpStyle.Render("Type a name and press Enter • q to quit"),
+		HelpStyle.Render("Type a name and press Enter • esc to quit"),
 	)
+
+	if m.nameErr != "" {
+		body += "\n" + WarnStyle.Render("⚠ "+m.nameErr)
+	}
+
+	if m.nameTaken {
+		body += "\n" + WarnStyle.Render(fmt.Sprintf(
+			"⚠ %q already exists and is not empty; create will abort unless run with --force or --merge.",
+			m.checkedName,
+		))
+	}
+	return BoxStyle.Render(body)
+}
+
+func (m WizardModel) viewModule() string {
+	body := fmt.Sprintf(
+		"%s\n%s\n\n%s\n\n%s",
+		TitleStyle.Render("Go module path"),
+		QuestionStyle.Render("What module path should the backend use?"),
+		m.moduleInput.View(),
+		HelpStyle.Render("Edit the path and press Enter • esc to quit"),
+	)
+	if m.moduleErr != "" {
+		body += "\n" + WarnStyle.Render("⚠ "+m.moduleErr)
+	}
 	return BoxStyle.Render(body)
 }
 
@@ -299,22 +455,34 @@ func (m WizardModel) viewSummary() string {
 		name = "my-project"
 	}
 	fw, _ := m.frameworkList.SelectedValue()
+	layout, _ := m.layoutList.SelectedValue()
 	db, _ := m.dbList.SelectedValue()
 	fe, _ := m.frontendList.SelectedValue()
+	rt, _ := m.runtimeList.SelectedValue()
 
 	var b strings.Builder
 	b.WriteString(TitleStyle.Render("Summary"))
 	b.WriteString("\n\n")
 	b.WriteString(fmt.Sprintf("Project:    %s\n", OptionStyle.Render(name)))
+	b.WriteString(fmt.Sprintf("Module:     %s\n", OptionStyle.Render(m.result.ModulePath)))
 	b.WriteString(fmt.Sprintf("Backend:    %s\n", OptionStyle.Render(fw)))
+	b.WriteString(fmt.Sprintf("Layout:     %s\n", OptionStyle.Render(layout)))
 	b.WriteString(fmt.Sprintf("Database:   %s\n", OptionStyle.Render(db)))
 	b.WriteString(fmt.Sprintf("Frontend:   %s\n", OptionStyle.Render(fe)))
-	b.WriteString(fmt.Sprintf("Runtime:    %s\n", OptionStyle.Render("bun")))
+	if (generator.Config{Frontend: fe}).HasFrontend() {
+		b.WriteString(fmt.Sprintf("Runtime:    %s\n", OptionStyle.Render(rt)))
+	}
 	docker := "no"
 	if m.result.UseDocker {
 		docker = "yes"
 	}
-	b.WriteString(fmt.Sprintf("Docker:     %s\n\n", OptionStyle.Render(docker)))
+	b.WriteString(fmt.Sprintf("Port:       %s\n", OptionStyle.Render(strconv.Itoa(m.result.Port))))
+	b.WriteString(fmt.Sprintf("Docker:     %s\n", OptionStyle.Render(docker)))
+	embed := "no"
+	if m.result.EmbedFrontend {
+		embed = "yes"
+	}
+	b.WriteString(fmt.Sprintf("Embed:      %s\n\n", OptionStyle.Render(embed)))
 	b.WriteString(
 		HelpStyle.Render("Press Enter/y to create the project, h to go back, q to quit."),
 	)